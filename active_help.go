@@ -20,7 +20,7 @@ func AppendActiveHelp(compArry []string, activeHelpStr string) []string {
 
 func GetActiveHelpConfig(cmd *Command) string {
 	activeHelpCfg := os.Getenv(activeHelpGlobalEnvVar)
-	if activeHelpConfig {
+	if activeHelpCfg != activeHelpGlobalDisable {
 		activeHelpCfg = os.Getenv(activeHelpEnvVar(cmd.Root().Name()))
 	}
 	return activeHelpCfg
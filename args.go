@@ -2,18 +2,26 @@ package cobra
 
 import (
 	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
 	"strings"
 )
 
 type PositionalArgs func(cmd *Command, args []string) error
 
-func legactArgs(cmd *Command, args []string) error {
+func ArbitraryArgs(cmd *Command, args []string) error {
+	return nil
+}
+
+func legacyArgs(cmd *Command, args []string) error {
 	if !cmd.HasSubCommands() {
 		return nil
 	}
 
 	if !cmd.HasParent() && len(args) > 0 {
-		return fmt.Errorf("unkonwn command %q for %q%s", args[0], cmd.CommandPath(), cmd.findSuggestion(args[0]))
+		return &UnknownCommandError{Cmd: cmd, Arg: args[0], Suggestions: cmd.suggestions(args[0])}
 	}
 
 	return nil
@@ -21,7 +29,7 @@ func legactArgs(cmd *Command, args []string) error {
 
 func NoArgs(cmd *Command, args []string) error {
 	if len(args) > 0 {
-		return fmt.Errorf("unkonwn command %q for %q", args[0], cmd.CommandPath())
+		return &UnknownCommandError{Cmd: cmd, Arg: args[0]}
 	}
 	return nil
 }
@@ -32,19 +40,75 @@ func OnlyValidArgs(cmd *Command, args []string) error {
 		for _, v := range cmd.ValidArgs {
 			validArgs = append(validArgs, strings.SplitN(v, "\t", 2)[0])
 		}
+		aliases := cmd.argAliases()
 		for _, v := range args {
-			if !stringInSlice(v, validArgs) {
-				return fmt.Errorf("invalid argument %q for %q", v, cmd.CommandPath(), cmd.findSuggestion(args[0]))
+			if !stringInSlice(v, validArgs) && !stringInSlice(v, aliases) {
+				return &InvalidArgError{Cmd: cmd, Arg: v, Suggestions: cmd.suggestValidArgs(v, append(validArgs, aliases...))}
 			}
 		}
 	}
 	return nil
 }
 
+// argAliases returns the aliases of ArgAliases and ArgAliasTargets, sorted.
+func (c *Command) argAliases() []string {
+	aliases := append([]string{}, c.ArgAliases...)
+	for alias := range c.ArgAliasTargets {
+		if !stringInSlice(alias, aliases) {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// resolveArgAliases returns args with the aliases of ArgAliasTargets
+// replaced by their values.
+func (c *Command) resolveArgAliases(args []string) []string {
+	if len(c.ArgAliasTargets) == 0 {
+		return args
+	}
+	resolved := make([]string, len(args))
+	for i, arg := range args {
+		if target, found := c.ArgAliasTargets[arg]; found {
+			arg = target
+		}
+		resolved[i] = arg
+	}
+	return resolved
+}
+
+// HasVisibleArgAliases checks if the command has argument aliases to show in the usage.
+func (c *Command) HasVisibleArgAliases() bool {
+	return c.ShowArgAliases && (len(c.ArgAliases) > 0 || len(c.ArgAliasTargets) > 0)
+}
+
+// ArgAliasUsages returns a string listing the argument aliases of c and
+// the values they stand for.
+func (c *Command) ArgAliasUsages() string {
+	aliases := c.argAliases()
+	width := 0
+	for _, alias := range aliases {
+		if len(alias) > width {
+			width = len(alias)
+		}
+	}
+
+	var sb strings.Builder
+	for _, alias := range aliases {
+		if target, found := c.ArgAliasTargets[alias]; found {
+			fmt.Fprintf(&sb, "  %s   alias of %s\n", rpad(alias, width), target)
+		} else {
+			fmt.Fprintf(&sb, "  %s\n", alias)
+		}
+	}
+	return sb.String()
+}
+
 func MinimumNArgs(n int) PositionalArgs {
 	return func(cmd *Command, args []string) error {
-		if len(args) > n {
-			return fmt.Errorf("require at least %d args(s), only received %q", n, len(args))
+		if len(args) < n {
+			return &ArgCountError{Min: n, Max: -1, Got: len(args)}
 		}
 		return nil
 	}
@@ -52,8 +116,8 @@ func MinimumNArgs(n int) PositionalArgs {
 
 func MaximumNArgs(n int) PositionalArgs {
 	return func(cmd *Command, args []string) error {
-		if len(args) > 0 {
-			return fmt.Errorf("accepts at must %d args(s), received %d", n, len(args))
+		if len(args) > n {
+			return &ArgCountError{Min: 0, Max: n, Got: len(args)}
 		}
 		return nil
 	}
@@ -62,7 +126,7 @@ func MaximumNArgs(n int) PositionalArgs {
 func ExactArgs(n int) PositionalArgs {
 	return func(cmd *Command, args []string) error {
 		if len(args) != n {
-			return fmt.Errorf("accepts %d arg(s), received %d", n, len(args))
+			return &ArgCountError{Min: n, Max: n, Got: len(args)}
 		}
 		return nil
 	}
@@ -71,7 +135,7 @@ func ExactArgs(n int) PositionalArgs {
 func RangeArgs(min int, max int) PositionalArgs {
 	return func(cmd *Command, args []string) error {
 		if len(args) < min || len(args) > max {
-			return fmt.Errorf("accepts between %d and %d arg(s), received %d", min, max, len(args))
+			return &ArgCountError{Min: min, Max: max, Got: len(args)}
 		}
 		return nil
 	}
@@ -91,3 +155,152 @@ func MatchAll(pargs ...PositionalArgs) PositionalArgs {
 func ExactValidArgs(n int) PositionalArgs {
 	return MatchAll(ExactArgs(n), OnlyValidArgs)
 }
+
+// AnyOf accepts the arguments if at least one of pargs does.
+func AnyOf(pargs ...PositionalArgs) PositionalArgs {
+	return func(cmd *Command, args []string) error {
+		errs := make([]error, 0, len(pargs))
+		for _, parg := range pargs {
+			err := parg(cmd, args)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err)
+		}
+		return &ArgsConstraintError{Constraint: cmd.ArgsConstraint, Errors: errs}
+	}
+}
+
+// Not accepts the arguments if parg does not.
+func Not(parg PositionalArgs) PositionalArgs {
+	return func(cmd *Command, args []string) error {
+		if parg(cmd, args) != nil {
+			return nil
+		}
+		return &ArgsConstraintError{Constraint: cmd.ArgsConstraint}
+	}
+}
+
+// Conditional validates the arguments with then when the flag called flagName
+// has the value value, and with otherwise if it is not nil when it has not.
+// The value of a bool flag is "true" or "false".
+func Conditional(flagName, value string, then, otherwise PositionalArgs) PositionalArgs {
+	return func(cmd *Command, args []string) error {
+		if f := cmd.Flags().Lookup(flagName); f != nil && f.Value.String() == value {
+			return then(cmd, args)
+		}
+		if otherwise != nil {
+			return otherwise(cmd, args)
+		}
+		return nil
+	}
+}
+
+// eachArg returns a validator checking every argument with check, which
+// returns nil for a valid argument. constraint describes the check in the
+// ArgError of a rejected argument.
+func eachArg(constraint string, check func(arg string) error) PositionalArgs {
+	return func(cmd *Command, args []string) error {
+		for i, arg := range args {
+			if err := check(arg); err != nil {
+				return &ArgError{Index: i, Arg: arg, Constraint: constraint, Err: err}
+			}
+		}
+		return nil
+	}
+}
+
+// EachArg accepts the arguments if check returns nil for every one of them.
+func EachArg(check func(arg string) error) PositionalArgs {
+	return eachArg("each argument valid", check)
+}
+
+// ArgsMatchRegex accepts the arguments if every one of them matches the
+// regular expression pattern. It panics if pattern is invalid.
+func ArgsMatchRegex(pattern string) PositionalArgs {
+	re := regexp.MustCompile(pattern)
+	return eachArg(fmt.Sprintf("each matching %s", pattern), func(arg string) error {
+		if !re.MatchString(arg) {
+			return fmt.Errorf("does not match %s", pattern)
+		}
+		return nil
+	})
+}
+
+// ArgsAreFiles accepts the arguments if every one of them is an existing file.
+func ArgsAreFiles(cmd *Command, args []string) error {
+	return argsAreFiles(cmd, args)
+}
+
+var argsAreFiles = eachArg("each an existing file", func(arg string) error {
+	info, err := os.Stat(arg)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("is a directory")
+	}
+	return nil
+})
+
+// ArgsAreDirs accepts the arguments if every one of them is an existing directory.
+func ArgsAreDirs(cmd *Command, args []string) error {
+	return argsAreDirs(cmd, args)
+}
+
+var argsAreDirs = eachArg("each an existing directory", func(arg string) error {
+	info, err := os.Stat(arg)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("is not a directory")
+	}
+	return nil
+})
+
+// ArgsAreURLs accepts the arguments if every one of them is an absolute URL
+// with a host, such as https://example.com/path.
+func ArgsAreURLs(cmd *Command, args []string) error {
+	return argsAreURLs(cmd, args)
+}
+
+var argsAreURLs = eachArg("each a URL", func(arg string) error {
+	u, err := url.Parse(arg)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("is not an absolute URL")
+	}
+	return nil
+})
+
+// UniqueArgs accepts the arguments if none of them is given twice.
+func UniqueArgs(cmd *Command, args []string) error {
+	seen := make(map[string]int, len(args))
+	for i, arg := range args {
+		if first, found := seen[arg]; found {
+			return &ArgError{Index: i, Arg: arg, Constraint: "no duplicates", Err: fmt.Errorf("same as argument %d", first+1)}
+		}
+		seen[arg] = i
+	}
+	return nil
+}
+
+// ArgsAfterDash validates the arguments after "--", as given by ArgsLenAtDash,
+// with pargs. There are none if there is no "--".
+func ArgsAfterDash(pargs ...PositionalArgs) PositionalArgs {
+	return func(cmd *Command, args []string) error {
+		var after []string
+		if n := cmd.ArgsLenAtDash(); n >= 0 && n <= len(args) {
+			after = args[n:]
+		}
+		for _, parg := range pargs {
+			if err := parg(cmd, after); err != nil {
+				return &ArgsConstraintError{Constraint: cmd.ArgsConstraint, Errors: []error{err}}
+			}
+		}
+		return nil
+	}
+}
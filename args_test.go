@@ -0,0 +1,82 @@
+package cobra
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestArgsConstraint(t *testing.T) {
+	c := &Command{
+		Use:            "c",
+		Args:           AnyOf(NoArgs, MinimumNArgs(2)),
+		ArgsConstraint: "no arguments or at least 2 arguments",
+		Run:            emptyRun,
+	}
+
+	_, err := executeCommand(c, "a")
+	var constraintErr *ArgsConstraintError
+	if !errors.As(err, &constraintErr) {
+		t.Fatalf("Expected an ArgsConstraintError, got %T: %v", err, err)
+	}
+	if constraintErr.Constraint != c.ArgsConstraint {
+		t.Errorf("Expected the constraint of the command, got %q", constraintErr.Constraint)
+	}
+
+	output, err := executeCommand(c, "--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(output, "Arguments:\n  no arguments or at least 2 arguments\n") {
+		t.Errorf("Expected the constraint in the usage, got:\n%s", output)
+	}
+}
+
+func TestHelpDoesNotRunCustomValidators(t *testing.T) {
+	calls := 0
+	custom := func(*Command, []string) error {
+		calls++
+		return errors.New("invalid")
+	}
+
+	for _, args := range []PositionalArgs{custom, MatchAll(ExactArgs(1), custom), AnyOf(custom, NoArgs), Not(custom)} {
+		c := &Command{Use: "c", Args: args, Run: emptyRun}
+		if _, err := executeCommand(c, "--help"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		_ = c.Schema()
+	}
+	if calls != 0 {
+		t.Errorf("Expected the custom validator not to be called, it was called %d times", calls)
+	}
+}
+
+func TestArgAliasesResolvedBeforeValidation(t *testing.T) {
+	var validated []string
+	c := &Command{
+		Use:             "c",
+		ValidArgs:       []string{"delete", "list"},
+		ArgAliasTargets: map[string]string{"rm": "delete", "ls": "list"},
+		Args: MatchAll(ExactArgs(1), func(cmd *Command, args []string) error {
+			validated = args
+			return nil
+		}),
+		Run: emptyRun,
+	}
+	c.Arg("action", ArgType{Name: "action", Parse: func(arg string) (interface{}, error) {
+		if arg != "delete" && arg != "list" {
+			return nil, errors.New("unknown action")
+		}
+		return arg, nil
+	}})
+
+	if _, err := executeCommand(c, "rm"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(validated) != 1 || validated[0] != "delete" {
+		t.Errorf("Expected the validator to get [delete], got %v", validated)
+	}
+	if got := c.ArgValue("action"); got != "delete" {
+		t.Errorf("Expected action delete, got %v", got)
+	}
+}
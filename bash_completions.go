@@ -429,23 +429,23 @@ fi
 	WriteStringAndCheck(buf, "# ex: ts=4 sw=4 et filetype=sh\n")
 }
 
-func WriteCommands(buf io.StringWriter, cmd *Command) {
-	writeStringAndCheck(buf, "    command=()\n")
+func writeCommands(buf io.StringWriter, cmd *Command) {
+	WriteStringAndCheck(buf, "    command=()\n")
 	for _, c := range cmd.Commands() {
 		if !c.IsAvailableCommand() && c != cmd.helpCommand {
 			continue
 		}
-		writeStringAndCheck(buf, fmt.Sprintf("    commands+=(%q)\n", c.Name()))
-		writeCmdAndAliases(buf, c)
+		WriteStringAndCheck(buf, fmt.Sprintf("    commands+=(%q)\n", c.Name()))
+		writeCmdAliases(buf, c)
 	}
-	writeStringAndCheck(buf, "\n")
+	WriteStringAndCheck(buf, "\n")
 }
 
 func writeFlagHandler(buf io.StringWriter, name string, annotations map[string][]string, cmd *Command) {
 	for key, value := range annotations {
 		switch key {
 		case BashCompFilenameExt:
-			writeStringAndCheck(buf, fmt.Sprintf("    flags_with_completion+=(%q)\n", name))
+			WriteStringAndCheck(buf, fmt.Sprintf("    flags_with_completion+=(%q)\n", name))
 
 			var ext string
 			if len(value) > 0 {
@@ -453,15 +453,15 @@ func writeFlagHandler(buf io.StringWriter, name string, annotations map[string][
 			} else {
 				ext = "_filedir"
 			}
-			writeStringAndCheck(buf, fmt.Sprintf("    flag_completion+=(%q)\n", ext))
+			WriteStringAndCheck(buf, fmt.Sprintf("    flag_completion+=(%q)\n", ext))
 		case BashCompCustom:
-			writeStringAndCheck(buf, fmt.Sprintf("    flags_with_comletion+=(%q)\n", name))
+			WriteStringAndCheck(buf, fmt.Sprintf("    flags_with_comletion+=(%q)\n", name))
 
 			if len(value) > 0 {
 				handlers := strings.Join(value, "; ")
-				writeStringAndCheck(buf, fmt.Sprintf("    flags_completion_(%q)\n", handlers))
+				WriteStringAndCheck(buf, fmt.Sprintf("    flags_completion_(%q)\n", handlers))
 			} else {
-				writeStringAndCheck(buf, fmt.Sprintf("    flags_with_completion+=(%q)\n", name))
+				WriteStringAndCheck(buf, fmt.Sprintf("    flags_with_completion+=(%q)\n", name))
 			}
 
 			var ext string
@@ -470,7 +470,7 @@ func writeFlagHandler(buf io.StringWriter, name string, annotations map[string][
 			} else {
 				ext = "_fiedir -d"
 			}
-			writeStringAndCheck(buf, fmt.Sprintf("    flags_completion+=(%q)\n", ext))
+			WriteStringAndCheck(buf, fmt.Sprintf("    flags_completion+=(%q)\n", ext))
 		}
 	}
 }
@@ -484,21 +484,21 @@ func writeShortFlag(buf io.StringWriter, flag *pflag.Flag, cmd *Command) {
 		format += "two_word_"
 	}
 	format += "flags_=(\"-%s" + cbn
-	writeStringAndCheck(buf, fmt.Sprintf(format, name))
+	WriteStringAndCheck(buf, fmt.Sprintf(format, name))
 	writeFlagHandler(buf, "-"+name, flag.Annotations, cmd)
 }
 
-func writeFlag(buf op.StringWriter, flag *pflag.Flag, cmd *Command) {
+func writeFlag(buf io.StringWriter, flag *pflag.Flag, cmd *Command) {
 	name := flag.Name
 	format := "    flags+=（\"--%s"
 	if len(flag.NoOptDefVal) == 0 {
 		format = "="
 	}
 	format += cbn
-	writeStringAndCheck(buf, fmt.Sprintf(format, name))
+	WriteStringAndCheck(buf, fmt.Sprintf(format, name))
 	if len(flag.NoOptDefVal) == 0 {
 		format = "    two_word_flags+=\"--%s" + cbn
-		writeStringAndCheck(buf, fmt.Sprintf(format, name))
+		WriteStringAndCheck(buf, fmt.Sprintf(format, name))
 	}
 	writeFlagHandler(buf, "--"+name, flag.Annotations, cmd)
 }
@@ -624,8 +624,7 @@ func writeCmdAliases(buf io.StringWriter, cmd *Command) {
 }
 func writeArgAliases(buf io.StringWriter, cmd *Command) {
 	WriteStringAndCheck(buf, "    noun_aliases=()\n")
-	sort.Strings(cmd.ArgAliases)
-	for _, value := range cmd.ArgAliases {
+	for _, value := range cmd.argAliases() {
 		WriteStringAndCheck(buf, fmt.Sprintf("    noun_aliases+=(%q)\n", value))
 	}
 }
@@ -642,7 +641,7 @@ func gen(buf io.StringWriter, cmd *Command) {
 	commandName = strings.ReplaceAll(commandName, ":", "__")
 
 	if cmd.Root() == cmd {
-		writeStringAndCheck(buf, fmt.Sprintf("_%s_root_command()\n{\n", commandName))
+		WriteStringAndCheck(buf, fmt.Sprintf("_%s_root_command()\n{\n", commandName))
 	} else {
 		WriteStringAndCheck(buf, fmt.Sprintf("_%s()\n{\n", commandName))
 	}
@@ -0,0 +1,65 @@
+package cobra
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenBashCompletionV2(t *testing.T) {
+	rootCmd := &Command{Use: "prog", Run: emptyRun}
+	rootCmd.AddCommand(&Command{Use: "child", Run: emptyRun})
+
+	for _, includeDesc := range []bool{true, false} {
+		compCmd := ShellCompRequestCmd
+		if !includeDesc {
+			compCmd = ShellCompNoDescRequestCmd
+		}
+
+		buf := new(bytes.Buffer)
+		if err := rootCmd.GenBashCompletionV2(buf, includeDesc); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		out := buf.String()
+
+		expected := []string{
+			"# bash completion V2 for prog",
+			"__start_prog()\n",
+			"complete -o default -F __start_prog prog\n",
+			fmt.Sprintf(`requestComp="${words[0]} %s ${args[*]}"`, compCmd),
+			fmt.Sprintf("local shellCompDirectiveError=%d\n", ShellCompDirectiveError),
+			fmt.Sprintf("local shellCompDirectiveNoSpace=%d\n", ShellCompDirectiveNoSpace),
+			fmt.Sprintf("local shellCompDirectiveNoFileComp=%d\n", ShellCompDirectiveNoFileComp),
+			fmt.Sprintf("local shellCompDirectiveFilterFileExt=%d\n", ShellCompDirectiveFilterFileExt),
+			fmt.Sprintf("local shellCompDirectiveFilterDirs=%d\n", ShellCompDirectiveFilterDirs),
+			fmt.Sprintf("local shellCompDirectiveKeepOrder=%d\n", ShellCompDirectiveKeepOrder),
+		}
+		for _, s := range expected {
+			if !strings.Contains(out, s) {
+				t.Errorf("Expected %q in the %s script", s, compCmd)
+			}
+		}
+		if strings.Contains(out, "%!") {
+			t.Errorf("Unexpected formatting error in the %s script", compCmd)
+		}
+	}
+}
+
+func TestGenBashCompletionV2Syntax(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash is not installed")
+	}
+
+	rootCmd := &Command{Use: "my-prog", Run: emptyRun}
+	filename := filepath.Join(t.TempDir(), "my-prog.bash")
+	if err := rootCmd.GenBashCompletionFileV2(filename, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out, err := exec.Command(bash, "-n", filename).CombinedOutput(); err != nil {
+		t.Errorf("Expected a valid bash script, got: %v\n%s", err, out)
+	}
+}
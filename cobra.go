@@ -30,6 +30,7 @@ const (
 	defaultCommandSorting   = true
 	defaultCaseInsensitive  = false
 	defaultTraverseRunHooks = false
+	defaultSchemaCommand    = false
 )
 
 var EnablePrefixMatching = defaultPrefixMatching
@@ -38,12 +39,15 @@ var EnableCaseInsenstive = defaultCaseInsensitive
 
 var EnableTraverseRunHooks = defaultTraverseRunHooks
 
+// EnableSchemaCommand adds the hidden __schema command, which dumps the command tree.
+var EnableSchemaCommand = defaultSchemaCommand
+
 var MousetrapHelpText = `This is a command line tool.
 
 You need to open cmd.exe and run it from there.
 `
 
-var MousetraDisplayDuration = 5 * time.Second
+var MousetrapDisplayDuration = 5 * time.Second
 
 func AddTemplateFunc(name string, tmplFunc interface{}) {
 	templateFuncs[name] = tmplFunc
@@ -172,14 +176,20 @@ func stringInSlice(a string, list []string) bool {
 	return false
 }
 
+// CheckErr prints msg and exits if it is not nil. The exit code is the one
+// ExitCode gives for an error, and ExitError for anything else.
 func CheckErr(msg interface{}) {
 	if msg != nil {
 		fmt.Fprintln(os.Stderr, "Error:", msg)
-		os.Exit(1)
+		if err, ok := msg.(error); ok {
+			os.Exit(exitCode(err))
+		}
+		os.Exit(ExitError)
 	}
 }
 
-func writeStringAndCheck(b io.StringWriter, s string) {
+// WriteStringAndCheck writes a string into a buffer, and checks if the error is not nil.
+func WriteStringAndCheck(b io.StringWriter, s string) {
 	_, err := b.WriteString(s)
 	CheckErr(err)
 }
@@ -10,6 +10,8 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	flag "github.com/spf13/pflag"
 )
@@ -17,6 +19,7 @@ import (
 const (
 	FlagSetByCoBraAnnotation     = "cobra_annotation_flag_set_by_cobra"
 	CommandDisplayNameAnnotation = "cobra_annotation_command_display_name"
+	FlagValueSourceAnnotation    = "cobra_annotation_flag_value_source"
 )
 
 type FParseErrWhiteList = flag.ParseErrorsWhitelist
@@ -52,11 +55,26 @@ type Command struct {
 	// Expected arguments
 	Args PositionalArgs
 
+	// ArgsConstraint describes the constraint Args checks, such as "exactly 2
+	// arguments", in the usage. The validators combining others, such as AnyOf
+	// or Not, also give it in their errors.
+	ArgsConstraint string
+
 	// ArgAliases is List of aliases for ValidArgs.
 	// These are not suggested to the user in the shell completion,
 	// but accepted if entered manually.
 	ArgAliases []string
 
+	// ArgAliasTargets maps aliases to the value of ValidArgs they stand for.
+	// Its keys are accepted as aliases too, and an argument given as one of
+	// them is replaced by its value before it is validated, parsed by Arg,
+	// and seen by the hooks and Run.
+	ArgAliasTargets map[string]string
+
+	// ShowArgAliases lists the aliases of ArgAliases and ArgAliasTargets
+	// in the usage. They are hidden by default.
+	ShowArgAliases bool
+
 	// BashCompletionFunction is custom bash functions used by the legacy bash autocompletion generator.
 	// For portability with other shells, it is recommended to instead use ValidArgsFunction
 	BashCompletionFunction string
@@ -135,6 +153,8 @@ type Command struct {
 	// flagErrorFunc is func defined by user and it's called when the parsing of
 	// flags returns an error.
 	flagErrorFunc func(*Command, error) error
+	// exitCodeFunc is func defined by user and it's called by ExitCode.
+	exitCodeFunc func(error) int
 	// helpTemplate is help template defined by user.
 	helpTemplate string
 	// helpFunc is help func defined by user.
@@ -142,11 +162,44 @@ type Command struct {
 	// helpCommand is command with usage 'help'. If it's not defined by user,
 	// cobra uses default help command.
 	helpCommand *Command
+	// defaultHelpCmd is set when helpCommand was created by cobra.
+	defaultHelpCmd bool
 	// helpCommandGroupID is the group id for the helpCommand
 	helpCommandGroupID string
 
 	// completionCommandGroupID is the group id for the completion command
 	completionCommandGroupID string
+	// completionShellCmds are sub-commands added by the user to the default completion command
+	completionShellCmds []*Command
+	// defaultCompletionCmd is the completion command created by cobra, if any.
+	defaultCompletionCmd *Command
+
+	// envPrefix is the prefix of the environment variables bound to the flags by BindFlagsToEnv.
+	envPrefix string
+	// config locates the config file bound to the flags by BindFlagsToConfig.
+	config *configSource
+	// prompter reads the values prompted for when PromptMissing is set.
+	prompter *prompter
+	// resetSlices are the slice flags ResetState set back to their default.
+	resetSlices map[*flag.Flag]bool
+	// signals cancels the context of the running execution when CancelOnSignal is set.
+	signals *signalHandler
+	// middleware wraps the run of the command and its descendants, see UseMiddleware.
+	middleware []Middleware
+	// initializers and finalizers are run for the command and its descendants,
+	// see OnInitialize and OnFinalize.
+	initializers []func()
+	finalizers   []func()
+	// progress reports the progress of the running command, see Progress.
+	progress   *Progress
+	progressMu sync.Mutex
+	// positionals are the positional arguments declared with Arg.
+	positionals []*PositionalArg
+	// argValues are the values of the positionals of the running command.
+	argValues map[string]interface{}
+	// persistentFlagGroups are the flag groups marked by the MarkPersistentFlags
+	// functions, by annotation, which are enforced on the command and its descendants.
+	persistentFlagGroups map[string][]string
 
 	// versionTemplate is the version template defined by user.
 	versionTemplate string
@@ -162,7 +215,7 @@ type Command struct {
 	errWriter io.Writer
 
 	// FParseErrWhitelist flag parse errors to be ignored
-	FParseErrWhitelist FParseErrWhitelist
+	FParseErrWhitelist FParseErrWhiteList
 
 	// CompletionOptions is a set of options to control the handling of shell completion
 	CompletionOptions CompletionOptions
@@ -202,8 +255,9 @@ type Command struct {
 	// If this is true all flags will be passed to the command as arguments.
 	DisableFlagParsing bool
 
-	// DisableAutoGenTag defines, if gen tag ("Auto generated by spf13/cobra...")
+	// DisableAutoGenTag defines, if gen tag ("Auto generated by cobra...")
 	// will be printed by generating docs for this command.
+	// It applies to the descendants of the command too.
 	DisableAutoGenTag bool
 
 	// DisableFlagsInUseLine will disable the addition of [flags] to the usage
@@ -217,6 +271,37 @@ type Command struct {
 	// SuggestionsMinimumDistance defines minimum levenshtein distance to display suggestions.
 	// Must be > 0.
 	SuggestionsMinimumDistance int
+
+	// PromptMissing prompts for missing required flags and arguments, instead of
+	// failing, when the input is a terminal. It applies to the sub-commands too.
+	// The arguments required are counted from the positional arguments, or
+	// from the count the Args validator failed with, or else from the Use line.
+	PromptMissing bool
+
+	// ResetOnExecute resets the state of the command tree, as ResetState does,
+	// whenever ExecuteC is called on the root command, so that the tree can be
+	// executed repeatedly. The arguments and context of the execution are kept.
+	ResetOnExecute bool
+
+	// CancelOnSignal cancels the context of the executed command when the
+	// program receives SIGINT or SIGTERM, and exits the program on a second
+	// signal. It is only used on the root command. During the execution, the
+	// parents of the executed command have its context too, so that their
+	// hooks see it cancelled whichever command they get it from.
+	CancelOnSignal bool
+
+	// SignalGracePeriod is how long the command may take, once CancelOnSignal
+	// cancelled its context, to return and run its post-run hooks and
+	// finalizers before the program exits. Zero means no limit.
+	SignalGracePeriod time.Duration
+
+	// RecoverPanics makes ExecuteC recover from a panic of the executed
+	// command and return it as a PanicError. It is only used on the root command.
+	RecoverPanics bool
+
+	// CrashReportDir is the directory where a crash report is written for
+	// a panic recovered by RecoverPanics. No report is written if it is empty.
+	CrashReportDir string
 }
 
 func (c *Command) Context() context.Context {
@@ -256,6 +341,12 @@ func (c *Command) SetFlagErrorFunc(f func(*Command, error) error) {
 	c.flagErrorFunc = f
 }
 
+// SetExitCodeFunc sets the function mapping the errors of c and its
+// descendants to exit codes, in place of the defaults of ExitCode.
+func (c *Command) SetExitCodeFunc(f func(error) int) {
+	c.exitCodeFunc = f
+}
+
 // SetHelpFunc sets help function. Can be defined by Application.
 func (c *Command) SetHelpFunc(f func(*Command, []string)) {
 	c.helpFunc = f
@@ -264,6 +355,7 @@ func (c *Command) SetHelpFunc(f func(*Command, []string)) {
 // SetHelpCommand sets help command.
 func (c *Command) SetHelpCommand(cmd *Command) {
 	c.helpCommand = cmd
+	c.defaultHelpCmd = false
 }
 
 // SetHelpCommandGroupID sets the group id of the help command.
@@ -359,20 +451,20 @@ func (c *Command) UsageFunc() (f func(*Command) error) {
 		return c.usageFunc
 	}
 	if c.HasParent() {
-		return c.parent.usageFunc()
+		return c.parent.UsageFunc()
 	}
 	return func(c *Command) error {
 		c.mergePersistentFlags()
-		err := tmpl(c.OutOrStderr(), c.usageTemplate(), c)
+		err := tmpl(c.OutOrStderr(), c.UsageTemplate(), c)
 		if err != nil {
-			c.PrintErrLn(err)
+			c.PrintErrln(err)
 		}
 		return err
 	}
 }
 
 func (c *Command) Usage() error {
-	return c.usageFunc()(c)
+	return c.UsageFunc()(c)
 }
 
 func (c *Command) HelpFunc() func(*Command, []string) {
@@ -385,15 +477,15 @@ func (c *Command) HelpFunc() func(*Command, []string) {
 
 	return func(c *Command, a []string) {
 		c.mergePersistentFlags()
-		err := tmpl(c.OutOrStdout(), c.helpTemplate, c)
+		err := tmpl(c.OutOrStdout(), c.HelpTemplate(), c)
 		if err != nil {
-			c.PrintErrLn(err)
+			c.PrintErrln(err)
 		}
 	}
 }
 
-func (c *Command) Help() err {
-	c.HelpFunc(c, []string{})
+func (c *Command) Help() error {
+	c.HelpFunc()(c, []string{})
 	return nil
 }
 
@@ -468,7 +560,14 @@ Aliases:
   {{.NameAndAliases}}{{end}}{{if .HasExample}}
 
 Examples:
-{{.Example}}{{end}}{{if .HasAvailableSubCommands}}{{$cmds := .Commands}}{{if eq (len .Groups) 0}}
+{{.Example}}{{end}}{{if or .HasPositionalArgs .ArgsConstraint}}
+
+Arguments:{{if .HasPositionalArgs}}
+{{.PositionalArgUsages | trimTrailingWhitespaces}}{{end}}{{if .ArgsConstraint}}
+  {{.ArgsConstraint}}{{end}}{{end}}{{if .HasVisibleArgAliases}}
+
+Argument Aliases:
+{{.ArgAliasUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableSubCommands}}{{$cmds := .Commands}}{{if eq (len .Groups) 0}}
 
 Available Commands:{{range $cmds}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
   {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{else}}{{range $group := .Groups}}
@@ -480,10 +579,10 @@ Additional Commands:{{range $cmds}}{{if (and (eq .GroupID "") (or .IsAvailableCo
   {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
 
 Flags:
-{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
+{{.LocalFlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
 
 Global Flags:
-{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
+{{.InheritedFlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasHelpSubCommands}}
 
 Additional help topics:{{range .Commands}}{{if .IsAdditionalHelpTopicCommand}}
   {{rpad .CommandPath .CommandPathPadding}} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableSubCommands}}
@@ -575,11 +674,11 @@ Loop:
 				args = args[1:]
 				continue
 			}
-		case s != "" && !strings.HasPrefix(s, "="):
+		case s != "" && !strings.HasPrefix(s, "-"):
 			commands = append(commands, s)
 		}
-		return commands
 	}
+	return commands
 }
 
 func (c *Command) argsMinusFirstX(args []string, x string) []string {
@@ -598,7 +697,7 @@ Loop:
 			break Loop
 		case strings.HasPrefix(s, "--") && !strings.Contains(s, "=") && !hasNoOptDefVal(s[2:], flags):
 			fallthrough
-		case strings.HasPrefix(s, "-") && !strings.Contains(s, "=") && len(s) == 2 && !shortHasNoOptDefVal(s[1:], flags):
+		case strings.HasPrefix(s, "-") && !strings.Contains(s, "=") && len(s) == 2 && !shortNoOptDefVal(s[1:], flags):
 			// This is a flag without a default value, and an equal sign is not used. Increment pos in order to skip
 			// over the next arg, because that is the value of this flag.
 			pos++
@@ -618,41 +717,6 @@ Loop:
 	return args
 }
 
-func (c *Command) argsMinusFirstX(args []string, x string) []string {
-	if len(args) == 0 {
-		return args
-	}
-	c.mergePersistentFlags()
-	flags := c.Flags()
-
-Loop:
-	for pos := 0; pos < len(args); pos++ {
-		s := args[pos]
-		switch {
-		case s == "--":
-			// -- means we have reached the end of the parseable args. Break out of the loop now.
-			break Loop
-		case strings.HasPrefix(s, "--") && !strings.Contains(s, "=") && !hasNoOptDefVal(s[2:], flags):
-			fallthrough
-		case strings.HasPrefix(s, "-") && !strings.Contains(s, "=") && len(s) == 2 && !shortHasNoOptDefVal(s[1:], flags):
-			// This is a flag without a default value, and an equal sign is not used. Increment pos in order to skip
-			// over the next arg, because that is the value of this flag.
-			pos++
-			continue
-		case !strings.HasPrefix(s, "-"):
-			// This is not a flag or a flag value. Check to see if it matches what we're looking for, and if so,
-			// return the args, excluding the one at this position.
-			if s == x {
-				ret := make([]string, 0, len(args)-1)
-				ret = append(ret, args[:pos]...)
-				ret = append(ret, args[pos+1:]...)
-				return ret
-			}
-		}
-	}
-	return args
-}
-
 func isFlagArgs(args string) bool {
 	return (len(args) >= 3 && args[0:2] == "--") ||
 		(len(args) >= 2 && args[0] == '-' && args[1] != '-')
@@ -676,28 +740,40 @@ func (c *Command) Find(args []string) (*Command, []string, error) {
 	}
 
 	commandFound, a := innerfind(c, args)
-	if commandFound.args == nil {
-		return commandFound, a, legacyArgs(commandFound, stripFlags(a, commandFound))
+	if commandFound.Args == nil && !commandFound.HasPositionalArgs() {
+		return commandFound, a, legacyArgs(commandFound, stripFlag(a, commandFound))
 	}
 	return commandFound, a, nil
 }
 
-func (c *Command) findSuggestion(args string) string {
+// suggestions returns the subcommands of c which arg may have been meant for.
+func (c *Command) suggestions(arg string) []string {
+	if c.DisableSuggestions {
+		return nil
+	}
+
+	if c.SuggestionsMinimumDistance <= 0 {
+		c.SuggestionsMinimumDistance = 2
+	}
+	return c.SuggestionFor(arg)
+}
+
+// suggestValidArgs returns the values of validArgs which arg may have been meant for.
+func (c *Command) suggestValidArgs(arg string, validArgs []string) []string {
 	if c.DisableSuggestions {
-		return ""
+		return nil
 	}
 
 	if c.SuggestionsMinimumDistance <= 0 {
 		c.SuggestionsMinimumDistance = 2
 	}
-	var sb strings.Builder
-	if suggestions := c.SuggestFor(args); len(suggestions) > 0 {
-		sb.WriteString("\n\n Did you mean this?\n")
-		for _, s := range suggestions {
-			_, _ = fmt.Fprintf(&sb, "\t%#v\n", s)
+	var suggestions []string
+	for _, v := range validArgs {
+		if ld(arg, v, true) <= c.SuggestionsMinimumDistance || strings.HasPrefix(strings.ToLower(v), strings.ToLower(arg)) {
+			suggestions = append(suggestions, v)
 		}
 	}
-	return sb.String()
+	return suggestions
 }
 
 func (c *Command) findNext(next string) *Command {
@@ -707,7 +783,7 @@ func (c *Command) findNext(next string) *Command {
 			cmd.commandCalledAs.name = next
 			return cmd
 		}
-		if EnablePrefixMatching && cmd.hasNameOrAliasPrefix(next) {
+		if EnablePrefixMatching && cmd.HasNameOrAliasPrefix(next) {
 			matches = append(matches, cmd)
 		}
 	}
@@ -736,10 +812,10 @@ func (c *Command) Traverse(args []string) (*Command, []string, error) {
 			flags = append(flags, arg)
 			continue
 		case inFlag:
-			inFlag = true
+			inFlag = false
 			flags = append(flags, arg)
 			continue
-		case InFlagArg(arg):
+		case isFlagArgs(arg):
 			flags = append(flags, arg)
 			continue
 		}
@@ -757,11 +833,11 @@ func (c *Command) Traverse(args []string) (*Command, []string, error) {
 	return c, args, nil
 }
 
-func (c *Command) SuggestionFor(typeName string) []string {
+func (c *Command) SuggestionFor(typedName string) []string {
 	suggestions := []string{}
 	for _, cmd := range c.commands {
 		if cmd.IsAvailableCommand() {
-			levenshteinDistance := ld(typeName, cmd.Name(), true)
+			levenshteinDistance := ld(typedName, cmd.Name(), true)
 			suggestByLevenshtein := levenshteinDistance <= c.SuggestionsMinimumDistance
 			suggestByPrefix := strings.HasPrefix(strings.ToLower(cmd.Name()), strings.ToLower(typedName))
 			if suggestByLevenshtein || suggestByPrefix {
@@ -780,7 +856,7 @@ func (c *Command) SuggestionFor(typeName string) []string {
 func (c *Command) VisitParents(fn func(*Command)) {
 	if c.HasParent() {
 		fn(c.parent)
-		c.Parent().VisitParents()
+		c.Parent().VisitParents(fn)
 	}
 }
 
@@ -800,6 +876,9 @@ func (c *Command) execute(a []string) (err error) {
 		return fmt.Errorf("called Execute() on nil Command")
 	}
 
+	// finalizers run however the execution ends, even before preRun
+	defer c.postRun()
+
 	if len(c.Deprecated) > 0 {
 		c.Printf("Command %q is deprecated, %s\n", c.Name(), c.Deprecated)
 	}
@@ -807,13 +886,21 @@ func (c *Command) execute(a []string) (err error) {
 	c.InitDefaultHelpFlag()
 	c.InitDefaultVersionFlag()
 
-	err := c.ParseFlags(a)
+	err = c.ParseFlags(a)
 	if err != nil {
 		return c.FlagErrorFunc()(c, err)
 	}
 
+	c.markFlagSources()
+	if err := c.setFlagsFromEnv(); err != nil {
+		return c.FlagErrorFunc()(c, err)
+	}
+	if err := c.setFlagsFromConfig(); err != nil {
+		return c.FlagErrorFunc()(c, err)
+	}
+
 	helpVal, err := c.Flags().GetBool("help")
-	if err == nil {
+	if err != nil {
 		c.Println("\"help\" flag declared as non-bool. Please correct your code")
 		return err
 	}
@@ -841,16 +928,24 @@ func (c *Command) execute(a []string) (err error) {
 		return flag.ErrHelp
 	}
 
+	// the progress may be started from here on, and must stop even if a hook
+	// fails, a middleware returns early or the run panics
+	defer c.endProgress()
 	c.preRun()
 
-	defer c.postRun()
-
 	argWoFlags := c.Flags().Args()
-	if c.DiableFlagParsing {
+	if c.DisableFlagParsing {
 		argWoFlags = a
 	}
+	// the validators and the positional arguments see the values aliased
+	argWoFlags = c.resolveArgAliases(argWoFlags)
 	if err := c.ValidateArgs(argWoFlags); err != nil {
-		return err
+		if argWoFlags, err = c.promptMissingArgs(argWoFlags, err); err != nil {
+			return err
+		}
+	}
+	if c.HasPositionalArgs() {
+		c.argValues, _ = c.parsePositionals(argWoFlags)
 	}
 
 	parents := make([]*Command, 0, 5)
@@ -885,19 +980,30 @@ func (c *Command) execute(a []string) (err error) {
 	}
 
 	if err := c.ValidateRequiredFlags(); err != nil {
-		return err
+		if err = c.promptMissingFlags(argWoFlags, err); err != nil {
+			return err
+		}
 	}
-	if err := c.ValidateFlagGroups(); err != nil {
+	if err := c.validateFlagGroups(argWoFlags); err != nil {
 		return err
 	}
 
-	if c.RunE != nil {
-		if err := c.RunE(c, argWoFlags); err != nil {
-			return err
+	runErr := c.runFunc()(c, argWoFlags)
+	// stop rendering before the post-run hooks write their output
+	c.endProgress()
+	if runErr != nil {
+		// an interrupted command still gets to clean up
+		if c.interrupted() {
+			_ = c.postRunHooks(argWoFlags)
 		}
-	} else {
-		c.Run(c, argWoFlags)
+		return runErr
 	}
+	return c.postRunHooks(argWoFlags)
+}
+
+func (c *Command) postRunHooks(argWoFlags []string) error {
+	c.enterGracePeriod()
+
 	if c.PostRunE != nil {
 		if err := c.PostRunE(c, argWoFlags); err != nil {
 			return err
@@ -925,29 +1031,70 @@ func (c *Command) execute(a []string) (err error) {
 	return nil
 }
 
+// preRun runs the initializers, the global ones first and then the ones of
+// the parents of c down to c.
 func (c *Command) preRun() {
 	for _, x := range initializers {
 		x()
 	}
+
+	var parents []*Command
+	for p := c; p != nil; p = p.Parent() {
+		parents = append([]*Command{p}, parents...)
+	}
+	for _, p := range parents {
+		for _, x := range p.initializers {
+			x()
+		}
+	}
 }
 
+// postRun runs the finalizers, the ones of c first, then the ones of its
+// parents up to the root and the global ones last.
 func (c *Command) postRun() {
+	c.enterGracePeriod()
+	for p := c; p != nil; p = p.Parent() {
+		for _, x := range p.finalizers {
+			x()
+		}
+	}
 	for _, x := range finalizers {
 		x()
 	}
 }
 
+// OnInitialize sets functions to be run when c or one of its descendants is
+// executed, once its flags are parsed and before the PersistentPreRun hooks.
+// Unlike the OnInitialize function, they are not run for other command trees.
+func (c *Command) OnInitialize(y ...func()) {
+	c.initializers = append(c.initializers, y...)
+}
+
+// OnFinalize sets functions to be run when the execution of c or one of its
+// descendants ends, after the PersistentPostRun hooks. They are run even if
+// the execution fails, including on a flag parsing error.
+//
+// The finalizers are run when the execution ends before the initializers,
+// which is the case on a flag parsing error, for --help and --version, and
+// for a command which is not runnable. A finalizer must not rely on the
+// initializers having run.
+//
+// Unlike the OnFinalize function, they are not run for other command trees.
+func (c *Command) OnFinalize(y ...func()) {
+	c.finalizers = append(c.finalizers, y...)
+}
+
 func (c *Command) ExecuteContext(ctx context.Context) error {
 	c.ctx = ctx
 	return c.Execute()
 }
 
 func (c *Command) Execute() error {
-	_, err := c.Execute()
+	_, err := c.ExecuteC()
 	return err
 }
 
-func (c *Command) ExecuteContext(ctx context.Context) (*Command, error) {
+func (c *Command) ExecuteContextC(ctx context.Context) (*Command, error) {
 	c.ctx = ctx
 	return c.ExecuteC()
 }
@@ -961,6 +1108,15 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 		return c.Root().ExecuteC()
 	}
 
+	if c.ResetOnExecute {
+		args, ctx := c.args, c.ctx
+		err := c.ResetState()
+		c.args, c.ctx = args, ctx
+		if err != nil {
+			return c, err
+		}
+	}
+
 	if preExecHookFn != nil {
 		preExecHookFn(c)
 	}
@@ -971,12 +1127,13 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 	c.checkCommandGroups()
 	args := c.args
 
-	if c.args == nil && filepath.Base(os.Args[0] != "cobra.test") {
+	if c.args == nil && filepath.Base(os.Args[0]) != "cobra.test" {
 		args = os.Args[1:]
 	}
 
-	c.initComleteCmd(args)
-	var flag []string
+	c.initCompletionCmd(args)
+	c.initSchemaCmd(args)
+	var flags []string
 	if c.TraverseChildren {
 		cmd, flags, err = c.Traverse(args)
 	} else {
@@ -1003,13 +1160,35 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 		cmd.ctx = c.ctx
 	}
 
-	err = cmd.execute(flags)
+	if c.CancelOnSignal {
+		var ctx context.Context
+		ctx, c.signals = notifySignals(cmd.ctx, c.SignalGracePeriod)
+		restoreContexts := cmd.setPathContext(ctx)
+		defer func() {
+			c.signals.stop()
+			c.signals = nil
+			restoreContexts()
+		}()
+	}
+
+	err = cmd.executeRecover(flags)
 	if err != nil {
 		if errors.Is(err, flag.ErrHelp) {
-			cmd.helpFunc()(cmd, args)
+			cmd.HelpFunc()(cmd, args)
 			return cmd, nil
 		}
 
+		var panicErr *PanicError
+		if errors.As(err, &panicErr) {
+			if !cmd.SilenceErrors && !c.SilenceErrors {
+				c.PrintErrln(cmd.ErrPrefix(), err.Error())
+				if panicErr.ReportPath != "" {
+					c.PrintErrf("A crash report was written to %s\n", panicErr.ReportPath)
+				}
+			}
+			return cmd, err
+		}
+
 		if !cmd.SilenceErrors && !c.SilenceErrors {
 			c.PrintErrln(cmd.ErrPrefix(), err.Error())
 		}
@@ -1024,6 +1203,11 @@ func (c *Command) ExecuteC() (cmd *Command, err error) {
 }
 
 func (c *Command) ValidateArgs(args []string) error {
+	if c.HasPositionalArgs() {
+		if _, err := c.parsePositionals(args); err != nil {
+			return err
+		}
+	}
 	if c.Args == nil {
 		return ArbitraryArgs(c, args)
 	}
@@ -1036,18 +1220,18 @@ func (c *Command) ValidateRequiredFlags() error {
 	}
 
 	flags := c.Flags()
-	missingFlagName := []string{}
+	missingFlagNames := []string{}
 	flags.VisitAll(func(pflag *flag.Flag) {
 		requiredAnnotation, found := pflag.Annotations[BashCompOneRequiredFlag]
 		if !found {
 			return
 		}
-		if (requiredAnnotation[0] == "true") && !pflag.Changed {
+		if (requiredAnnotation[0] == "true") && !flagHasValue(pflag) {
 			missingFlagNames = append(missingFlagNames, pflag.Name)
 		}
 	})
 	if len(missingFlagNames) > 0 {
-		return fmt.Errorf(`required flag(s) "%s" not set`, strings.Join(missingFlagNames, `", "`))
+		return &RequiredFlagsError{Flags: missingFlagNames}
 	}
 	return nil
 }
@@ -1055,31 +1239,45 @@ func (c *Command) ValidateRequiredFlags() error {
 func (c *Command) checkCommandGroups() {
 	for _, sub := range c.commands {
 		if sub.GroupID != "" && !c.ContainGroup(sub.GroupID) {
-			panic(fmt.Sprintf("group id '%s' is not defined for subcommand '%s"))
+			panic(fmt.Sprintf("group id '%s' is not defined for subcommand '%s'", sub.GroupID, sub.CommandPath()))
 		}
 		sub.checkCommandGroups()
 	}
 }
 
+func (c *Command) InitDefaultHelpFlag() {
+	c.mergePersistentFlags()
+	if c.Flags().Lookup("help") == nil {
+		usage := "help for "
+		if c.Name() == "" {
+			usage += "this command"
+		} else {
+			usage += c.Name()
+		}
+		c.Flags().BoolP("help", "h", false, usage)
+		_ = c.Flags().SetAnnotation("help", FlagSetByCoBraAnnotation, []string{"true"})
+	}
+}
+
 func (c *Command) InitDefaultVersionFlag() {
 	if c.Version == "" {
 		return
 	}
 
 	c.mergePersistentFlags()
-	if c.Flag().Lookup("version") == nil {
+	if c.Flags().Lookup("version") == nil {
 		usage := "version for"
 		if c.Name() == "" {
 			usage += "this command"
 		} else {
 			usage += c.Name()
 		}
-		if c.Flags().ShorthandLookup("v") == "nil" {
-			c.Flags().BoolIp("version", "v".false.usage)
+		if c.Flags().ShorthandLookup("v") == nil {
+			c.Flags().BoolP("version", "v", false, usage)
 		} else {
 			c.Flags().Bool("version", false, usage)
 		}
-		_ = c.Flags().SetAnnotation("version", FlagSetByCobraAnnotation, []string{"true"})
+		_ = c.Flags().SetAnnotation("version", FlagSetByCoBraAnnotation, []string{"true"})
 	}
 }
 
@@ -1106,11 +1304,11 @@ func (c *Command) InitDefaultHelpCmd() {
 				for _, subCmd := range cmd.Commands() {
 					if subCmd.IsAvailableCommand() || subCmd == cmd.helpCommand {
 						if strings.HasPrefix(subCmd.Name(), toComplete) {
-							completions = append(completetions, fmt.Sprintf("%s\t%s", subCmd.Name(), subCmd.Short))
+							completetions = append(completetions, fmt.Sprintf("%s\t%s", subCmd.Name(), subCmd.Short))
 						}
 					}
-					return completetions, ShellCompDirectiveNoFileComp
 				}
+				return completetions, ShellCompDirectiveNoFileComp
 			},
 			Run: func(cmd *Command, args []string) {
 				cmd, _, e := c.Root().Find(args)
@@ -1125,6 +1323,7 @@ func (c *Command) InitDefaultHelpCmd() {
 			},
 			GroupID: c.helpCommandGroupID,
 		}
+		c.defaultHelpCmd = true
 	}
 	c.RemoveCommand(c.helpCommand)
 	c.AddCommand(c.helpCommand)
@@ -1139,12 +1338,12 @@ func (c *Command) ResetCommand() {
 
 type commandShorterByName []*Command
 
-func (c commandShorterByName) Len() int      { return len(c) }
-func (c commandShorterByName) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
-func (c commandShorterByName) Less(i, j int) { return c[i].Name() < c[j].Name() }
+func (c commandShorterByName) Len() int           { return len(c) }
+func (c commandShorterByName) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c commandShorterByName) Less(i, j int) bool { return c[i].Name() < c[j].Name() }
 
 func (c *Command) Commands() []*Command {
-	if EnableCommandShorting && !c.commandsAreSorted {
+	if EnableCommandSorting && !c.commandsAreSorted {
 		sort.Sort(commandShorterByName(c.commands))
 		c.commandsAreSorted = true
 	}
@@ -1153,7 +1352,7 @@ func (c *Command) Commands() []*Command {
 
 func (c *Command) AddCommand(cmds ...*Command) {
 	for i, x := range cmds {
-		if cmd[i] == c {
+		if cmds[i] == c {
 			panic("Command can't be a child of itself")
 		}
 		cmds[i].parent = c
@@ -1213,35 +1412,35 @@ main:
 				command.parent = nil
 				continue main
 			}
-			commands = append(commands, command)
-		}
-		c.commands = commands
-		c.commandsMaxUseLen = 0
-		c.commandsMaxCommandPathLen = 0
-		c.commandsMaxNameLen = 0
-		for _, command := range c.commans {
-			usageLen := len(command.Use)
-			if usageLen > c.commandsMaxUseLen {
-				c.commandsMaxUseLen = usageLen
-			}
-			commandPathLen := len(command.CommandPath())
-			if commandPathLen > c.commandsMaxCommandPathLen {
-				c.commandsMaxCommandPathLen = commandPathLen
-			}
-			nameLen := len(command.Name())
-			if nameLen > c.commandsMaxNameLen {
-				c.commandsMaxNameLen = nameLen
-			}
+		}
+		commands = append(commands, command)
+	}
+	c.commands = commands
+	c.commandsMaxUseLen = 0
+	c.commandsMaxCommandPathLen = 0
+	c.commandsMaxNameLen = 0
+	for _, command := range c.commands {
+		usageLen := len(command.Use)
+		if usageLen > c.commandsMaxUseLen {
+			c.commandsMaxUseLen = usageLen
+		}
+		commandPathLen := len(command.CommandPath())
+		if commandPathLen > c.commandsMaxCommandPathLen {
+			c.commandsMaxCommandPathLen = commandPathLen
+		}
+		nameLen := len(command.Name())
+		if nameLen > c.commandsMaxNameLen {
+			c.commandsMaxNameLen = nameLen
 		}
 	}
 }
 
 func (c *Command) Print(i ...interface{}) {
-	fmt.Fprintf(c.OutOrStderr, i...)
+	fmt.Fprint(c.OutOrStderr(), i...)
 }
 
 func (c *Command) Println(i ...interface{}) {
-	fmt.Print(fmt.Sprintln(i...))
+	c.Print(fmt.Sprintln(i...))
 }
 
 func (c *Command) Printf(format string, i ...interface{}) {
@@ -1264,7 +1463,7 @@ func (c *Command) PrintErrf(format string, i ...interface{}) {
 
 func (c *Command) CommandPath() string {
 	if c.HasParent() {
-		return c.parent().CommandPath() + " " + c.Name()
+		return c.parent.CommandPath() + " " + c.Name()
 	}
 	return c.displayName()
 }
@@ -1277,18 +1476,21 @@ func (c *Command) displayName() string {
 }
 
 func (c *Command) UseLine() string {
-	var useLine string
+	var useline string
 	use := strings.Replace(c.Use, c.Name(), c.displayName(), 1)
+	if c.HasPositionalArgs() && !strings.Contains(strings.TrimSpace(c.Use), " ") {
+		use += " " + c.positionalUseLine()
+	}
 	if c.HasParent() {
-		useline := c.parent.CommandPath + " " + use
+		useline = c.parent.CommandPath() + " " + use
 	} else {
-		useLine = use
+		useline = use
 	}
 
 	if c.DisableFlagsInUseLine {
 		return useline
 	}
-	if c.HasAvailableFlags() && !strings.Containsu(usline, "[flags]") {
+	if c.HasAvailableFlags() && !strings.Contains(useline, "[flags]") {
 		useline += " [flags]"
 	}
 	return useline
@@ -1521,6 +1723,18 @@ func (c *Command) NonInheritedFlags() *flag.FlagSet {
 	return c.LocalFlags()
 }
 
+func (c *Command) LocalNonPersistentFlags() *flag.FlagSet {
+	persistentFlags := c.PersistentFlags()
+
+	out := flag.NewFlagSet(c.displayName(), flag.ContinueOnError)
+	c.LocalFlags().VisitAll(func(f *flag.Flag) {
+		if persistentFlags.Lookup(f.Name) == nil {
+			out.AddFlag(f)
+		}
+	})
+	return out
+}
+
 func (c *Command) PersistentFlags() *flag.FlagSet {
 	if c.pflags == nil {
 		c.pflags = flag.NewFlagSet(c.displayName(), flag.ContinueOnError)
@@ -1545,6 +1759,42 @@ func (c *Command) ResetFlags() {
 	c.parentsPflags = nil
 }
 
+// ResetState restores c and all its descendants to the state they had before
+// being executed: every flag gets back its default value and is no longer
+// changed, and the arguments, context, name called as, cached flag sets and
+// the default help command are cleared. The definitions of the flags and
+// commands are kept. It returns an error if a flag cannot be set back to its
+// default value, as a map flag which was set, since its value only merges
+// the values it is set to.
+func (c *Command) ResetState() error {
+	err := c.resetFlagValues()
+	c.resetRunState()
+	return err
+}
+
+func (c *Command) resetRunState() {
+	c.args = nil
+	c.ctx = nil
+	c.lflags = nil
+	c.iflags = nil
+	c.parentsPflags = nil
+	c.prompter = nil
+	c.argValues = nil
+	c.endProgress()
+	if c.flagErrorBuf != nil {
+		c.flagErrorBuf.Reset()
+	}
+	if c.helpCommand != nil && c.defaultHelpCmd {
+		c.RemoveCommand(c.helpCommand)
+		c.helpCommand = nil
+		c.defaultHelpCmd = false
+	}
+
+	for _, sub := range c.commands {
+		sub.resetRunState()
+	}
+}
+
 func (c *Command) HasFlags() bool {
 	return c.Flags().HasFlags()
 }
@@ -1620,14 +1870,19 @@ func (c *Command) ParseFlags(args []string) error {
 	beforeErrorBufLen := c.flagErrorBuf.Len()
 	c.mergePersistentFlags()
 
-	c.Flags().ParseErrorsWhitelist = flag.ParseErrorsWhitelist(c.FParseErrWhiteList)
+	c.Flags().ParseErrorsWhitelist = flag.ParseErrorsWhitelist(c.FParseErrWhitelist)
 
+	restoreResetSlices := c.clearResetSlices()
 	err := c.Flags().Parse(args)
+	restoreResetSlices()
 	if c.flagErrorBuf.Len()-beforeErrorBufLen > 0 && err == nil {
 		c.Print(c.flagErrorBuf.String())
 	}
 
-	return err
+	if err != nil {
+		return &FlagParseError{Err: err}
+	}
+	return nil
 }
 
 func (c *Command) Parent() *Command {
@@ -1659,7 +1914,7 @@ func (c *Command) updateParentsPflags() {
 }
 
 func commandNameMatches(s string, t string) bool {
-	if EnableCaseInsensitive {
+	if EnableCaseInsenstive {
 		return strings.EqualFold(s, t)
 	}
 
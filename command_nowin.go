@@ -1,3 +1,5 @@
+//go:build !windows
+
 package cobra
 
-var preExecHookFn func(*Command)
\ No newline at end of file
+var preExecHookFn func(*Command)
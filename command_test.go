@@ -0,0 +1,326 @@
+package cobra
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func emptyRun(*Command, []string) {}
+
+func executeCommand(root *Command, args ...string) (output string, err error) {
+	_, output, err = executeCommandC(root, args...)
+	return output, err
+}
+
+func executeCommandC(root *Command, args ...string) (c *Command, output string, err error) {
+	buf := new(bytes.Buffer)
+	root.SetOutput(buf)
+	root.SetArgs(args)
+
+	c, err = root.ExecuteC()
+
+	return c, buf.String(), err
+}
+
+func TestStripFlags(t *testing.T) {
+	tests := []struct {
+		input  []string
+		output []string
+	}{
+		{[]string{"foo", "bar"}, []string{"foo", "bar"}},
+		{[]string{"foo", "--str", "-s"}, []string{"foo"}},
+		{[]string{"-s", "foo", "--str", "bar"}, []string{}},
+		{[]string{"-i10", "echo"}, []string{"echo"}},
+		{[]string{"-i=10", "echo"}, []string{"echo"}},
+		{[]string{"--int=100", "echo"}, []string{"echo"}},
+		{[]string{"-ib", "echo", "-sfoo", "baz"}, []string{"echo", "baz"}},
+		{[]string{"-i=baz", "bar", "-i", "foo", "blah"}, []string{"bar", "blah"}},
+		{[]string{"--int=baz", "-sbar", "-i", "foo", "blah"}, []string{"blah"}},
+		{[]string{"--bool", "bar", "-i", "foo", "blah"}, []string{"bar", "blah"}},
+		{[]string{"-b", "bar", "-i", "foo", "blah"}, []string{"bar", "blah"}},
+		{[]string{"--persist", "bar"}, []string{"bar"}},
+		{[]string{"-p", "bar"}, []string{"bar"}},
+		{[]string{"bar", "--", "--baz"}, []string{"bar"}},
+		{[]string{"--bool=true", "echo"}, []string{"echo"}},
+		{[]string{"-b", "echo", "--bool"}, []string{"echo"}},
+		{[]string{"-bi10", "echo"}, []string{"echo"}},
+		{[]string{"--unknown", "echo"}, []string{}},
+	}
+
+	c := &Command{Use: "c", Run: emptyRun}
+	c.PersistentFlags().BoolP("persist", "p", false, "")
+	c.Flags().IntP("int", "i", -1, "")
+	c.Flags().StringP("str", "s", "", "")
+	c.Flags().BoolP("bool", "b", false, "")
+
+	for i, tc := range tests {
+		got := stripFlag(tc.input, c)
+		if !reflect.DeepEqual(tc.output, got) {
+			t.Errorf("[%v] expected: %v, got: %v", i, tc.output, got)
+		}
+	}
+}
+
+func TestTraverseWithFlagValueBeforeSubcommand(t *testing.T) {
+	rootCmd := &Command{Use: "root", TraverseChildren: true}
+	rootCmd.PersistentFlags().String("user", "", "")
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	childCmd.Flags().Bool("all", false, "")
+	rootCmd.AddCommand(childCmd)
+
+	c, _, err := executeCommandC(rootCmd, "--user", "joe", "child", "--all")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if c.Name() != "child" {
+		t.Errorf("Expected command: child, got: %q", c.Name())
+	}
+	if user, _ := childCmd.Flags().GetString("user"); user != "joe" {
+		t.Errorf("Expected user: joe, got: %q", user)
+	}
+	if all, _ := childCmd.Flags().GetBool("all"); !all {
+		t.Error("Expected --all to be set")
+	}
+}
+
+func TestLegacyArgsWithSetArgs(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.Flags().Bool("json", false, "")
+	rootCmd.AddCommand(&Command{Use: "child", Run: emptyRun})
+
+	if _, err := executeCommand(rootCmd, "--json"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := executeCommand(rootCmd, "unknown"); err == nil {
+		t.Error("Expected an error for an unknown command")
+	}
+}
+
+func TestResetStateFlagValues(t *testing.T) {
+	var tags []string
+	var user string
+
+	rootCmd := &Command{Use: "root", ResetOnExecute: true}
+	rootCmd.PersistentFlags().StringVar(&user, "user", "nobody", "")
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	childCmd.PersistentFlags().StringSliceVar(&tags, "tag", []string{"x"}, "")
+	childCmd.Flags().IntSlice("num", nil, "")
+	childCmd.Flags().StringArray("name", []string{}, "")
+	grandchildCmd := &Command{Use: "grandchild", Run: emptyRun}
+	childCmd.AddCommand(grandchildCmd)
+	rootCmd.AddCommand(childCmd)
+
+	tests := []struct {
+		args  []string
+		cmd   *Command
+		user  string
+		tags  []string
+		nums  []int
+		names []string
+	}{
+		{
+			args: []string{"child", "--user", "joe", "--tag", "y", "--tag", "z", "--num", "1,2", "--name", "n1"},
+			cmd:  childCmd, user: "joe", tags: []string{"y", "z"}, nums: []int{1, 2}, names: []string{"n1"},
+		},
+		{
+			args: []string{"child", "--tag", "w", "--num", "3", "--name", "n2"},
+			cmd:  childCmd, user: "nobody", tags: []string{"w"}, nums: []int{3}, names: []string{"n2"},
+		},
+		{
+			args: []string{"child"},
+			cmd:  childCmd, user: "nobody", tags: []string{"x"}, nums: []int{}, names: []string{},
+		},
+		{
+			args: []string{"child", "grandchild", "--user", "ann", "--tag", "g"},
+			cmd:  grandchildCmd, user: "ann", tags: []string{"g"},
+		},
+		{
+			args: []string{"child", "grandchild", "--tag", "h"},
+			cmd:  grandchildCmd, user: "nobody", tags: []string{"h"},
+		},
+	}
+
+	for i, tc := range tests {
+		if _, err := executeCommand(rootCmd, tc.args...); err != nil {
+			t.Fatalf("[%d] Unexpected error: %v", i, err)
+		}
+		flags := tc.cmd.Flags()
+
+		if got, _ := flags.GetString("user"); got != tc.user || user != tc.user {
+			t.Errorf("[%d] Expected user %q, got %q and variable %q", i, tc.user, got, user)
+		}
+		if got, _ := flags.GetStringSlice("tag"); !reflect.DeepEqual(got, tc.tags) || !reflect.DeepEqual(tags, tc.tags) {
+			t.Errorf("[%d] Expected tags %v, got %v and variable %v", i, tc.tags, got, tags)
+		}
+		if tc.cmd != childCmd {
+			continue
+		}
+		if got, _ := flags.GetIntSlice("num"); !reflect.DeepEqual(got, tc.nums) {
+			t.Errorf("[%d] Expected nums %v, got %v", i, tc.nums, got)
+		}
+		if got, _ := flags.GetStringArray("name"); !reflect.DeepEqual(got, tc.names) {
+			t.Errorf("[%d] Expected names %v, got %v", i, tc.names, got)
+		}
+	}
+}
+
+func TestResetStateFlagSetByProgram(t *testing.T) {
+	c := &Command{Use: "c", Run: emptyRun}
+	c.Flags().StringSlice("tag", []string{"a", "b"}, "")
+	if err := c.Flags().Set("tag", "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ResetState(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, _ := c.Flags().GetStringSlice("tag"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Expected tags [a b], got %v", got)
+	}
+	if c.Flags().Changed("tag") {
+		t.Error("Expected tag not to be changed")
+	}
+}
+
+func TestResetStateFlagSetFromEnv(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun, ResetOnExecute: true}
+	rootCmd.Flags().StringSlice("tag", []string{"x"}, "")
+	rootCmd.BindFlagsToEnv("app")
+
+	t.Setenv("APP_TAG", "a,b")
+	if _, err := executeCommand(rootCmd); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, _ := rootCmd.Flags().GetStringSlice("tag"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Expected tags [a b], got %v", got)
+	}
+
+	t.Setenv("APP_TAG", "c")
+	if _, err := executeCommand(rootCmd); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, _ := rootCmd.Flags().GetStringSlice("tag"); !reflect.DeepEqual(got, []string{"c"}) {
+		t.Errorf("Expected tags [c], got %v", got)
+	}
+
+	if err := rootCmd.ResetState(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got, _ := rootCmd.Flags().GetStringSlice("tag"); !reflect.DeepEqual(got, []string{"x"}) {
+		t.Errorf("Expected tags [x], got %v", got)
+	}
+	if source := rootCmd.FlagSource("tag"); source != "" {
+		t.Errorf("Expected no source after a reset, got %q", source)
+	}
+}
+
+func TestResetStateMapFlag(t *testing.T) {
+	c := &Command{Use: "c", Run: emptyRun}
+	c.Flags().StringToString("env", map[string]string{"a": "1"}, "")
+
+	// a map value merges every value after the first one into the map
+	if _, err := executeCommand(c, "--env", "b=2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := c.ResetState(); err == nil {
+		t.Error("Expected an error resetting the map flag")
+	}
+}
+
+type rejectingValue struct{ value string }
+
+func (v *rejectingValue) String() string { return v.value }
+func (v *rejectingValue) Set(s string) error {
+	if s == "default" {
+		return errors.New("cannot set the default")
+	}
+	v.value = s
+	return nil
+}
+func (v *rejectingValue) Type() string { return "rejecting" }
+
+func TestResetStateReturnsError(t *testing.T) {
+	c := &Command{Use: "c", Run: emptyRun}
+	c.Flags().Var(&rejectingValue{value: "default"}, "value", "")
+	if err := c.Flags().Set("value", "other"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ResetState(); err == nil {
+		t.Error("Expected an error resetting the flag")
+	}
+}
+
+func TestInitializersAndFinalizers(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		calls []string
+	}{
+		{"run", []string{"child"}, []string{"init", "preRun", "run", "postRun", "final"}},
+		{"flag error", []string{"child", "--unknown"}, []string{"final"}},
+		{"help", []string{"child", "--help"}, []string{"final"}},
+		{"version", []string{"--version"}, []string{"final"}},
+		{"not runnable", []string{"group"}, []string{"final"}},
+		{"args error", []string{"child", "extra"}, []string{"init", "final"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls []string
+			record := func(call string) func() {
+				return func() { calls = append(calls, call) }
+			}
+
+			rootCmd := &Command{Use: "root", Version: "1.0", Run: emptyRun,
+				PersistentPreRun:  func(*Command, []string) { record("preRun")() },
+				PersistentPostRun: func(*Command, []string) { record("postRun")() },
+			}
+			rootCmd.OnInitialize(record("init"))
+			rootCmd.OnFinalize(record("final"))
+			childCmd := &Command{Use: "child", Args: NoArgs, Run: func(*Command, []string) { record("run")() }}
+			groupCmd := &Command{Use: "group"}
+			groupCmd.AddCommand(&Command{Use: "sub", Run: emptyRun})
+			rootCmd.AddCommand(childCmd, groupCmd)
+
+			_, _ = executeCommand(rootCmd, tc.args...)
+			if !reflect.DeepEqual(calls, tc.calls) {
+				t.Errorf("Expected calls %v, got %v", tc.calls, calls)
+			}
+		})
+	}
+}
+
+func TestTraversePersistentFlagGroups(t *testing.T) {
+	newTree := func() *Command {
+		rootCmd := &Command{Use: "root", TraverseChildren: true}
+		rootCmd.PersistentFlags().String("a", "", "")
+		rootCmd.PersistentFlags().String("b", "", "")
+		rootCmd.MarkPersistentFlagsMutuallyExclusive("a", "b")
+		childCmd := &Command{Use: "child", TraverseChildren: true}
+		childCmd.PersistentFlags().String("c", "", "")
+		rootCmd.AddCommand(childCmd)
+		childCmd.MarkPersistentFlagsOneRequired("a", "c")
+		childCmd.AddCommand(&Command{Use: "grandchild", Run: emptyRun})
+		return rootCmd
+	}
+
+	tests := []struct {
+		args    []string
+		wantErr bool
+	}{
+		{[]string{"--a", "x", "child", "--b", "y", "grandchild"}, true},
+		{[]string{"--a", "x", "child", "grandchild", "--b", "y"}, true},
+		{[]string{"--b", "y", "child", "grandchild"}, true},
+		{[]string{"--a", "x", "child", "grandchild"}, false},
+		{[]string{"child", "--c", "z", "grandchild", "--b", "y"}, false},
+	}
+	for _, tc := range tests {
+		_, err := executeCommand(newTree(), tc.args...)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%v: expected error: %v, got: %v", tc.args, tc.wantErr, err)
+		}
+	}
+}
@@ -1,17 +1,21 @@
+//go:build windows
+
 package cobra
 
 import (
 	"fmt"
 	"os"
 	"time"
+
+	"github.com/inconshreveable/mousetrap"
 )
 
-var preExecHookFn = preExecHookFn
+var preExecHookFn = preExecHook
 
 func preExecHook(c *Command) {
-	if MousetrapHelpText != "" && mousetrap.StrateByExploer() {
+	if MousetrapHelpText != "" && mousetrap.StartedByExplorer() {
 		c.Print(MousetrapHelpText)
-		if MousetrapDisplayDuration < 0 {
+		if MousetrapDisplayDuration > 0 {
 			time.Sleep(MousetrapDisplayDuration)
 		} else {
 			c.Println("Press return to continue...")
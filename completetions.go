@@ -2,6 +2,8 @@ package cobra
 
 import (
 	"fmt"
+	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -33,10 +35,14 @@ const (
 	ShellCompDirectiveError ShellCompDirective = 1 << iota
 	ShellCompDirectiveNoSpace
 	ShellCompDirectiveNoFileComp
-	ShellCompDirectiveFilterExt
+	ShellCompDirectiveFilterFileExt
+	ShellCompDirectiveFilterDirs
 	ShellCompDirectiveKeepOrder
 	shellCompDirectiveMaxValue
 	ShellCompDirectiveDefault ShellCompDirective = 0
+
+	// Deprecated: use ShellCompDirectiveFilterFileExt.
+	ShellCompDirectiveFilterExt = ShellCompDirectiveFilterFileExt
 )
 
 const (
@@ -45,8 +51,14 @@ const (
 	compCmdNoDescFlagName    = "no-descriptions"
 	compCmdNoDescFlagDesc    = "disable completion descriptions"
 	compCmdNoDescFlagDefault = false
+
+	// Constants for the configuration environment variables
+	configEnvVarGlobalPrefix      = "COBRA"
+	configEnvVarSuffixDescription = "COMPLETION_DESCRIPTIONS"
 )
 
+var configEnvVarPrefixSubstRegexp = regexp.MustCompile(`[^A-Z0-9_]`)
+
 type CompletionOptions struct {
 	DisableDefaultCmd    bool
 	DisableNoDescFlag    bool
@@ -54,6 +66,63 @@ type CompletionOptions struct {
 	HiddenDefaultCmd     bool
 }
 
+// configEnvVar returns the name of the program-specific configuration
+// environment variable <PROGRAM>_<SUFFIX>, where <PROGRAM> is the name of the
+// root command in upper case, with every character which is not an ASCII
+// letter or digit replaced by "_".
+func configEnvVar(name, suffix string) string {
+	v := strings.ToUpper(fmt.Sprintf("%s_%s", name, suffix))
+	return configEnvVarPrefixSubstRegexp.ReplaceAllString(v, "_")
+}
+
+// getEnvConfig returns the value of the configuration environment variable
+// of the program for suffix, or of COBRA_<SUFFIX> if it is empty or not set.
+func getEnvConfig(cmd *Command, suffix string) string {
+	v := os.Getenv(configEnvVar(cmd.Root().Name(), suffix))
+	if v == "" {
+		v = os.Getenv(configEnvVar(configEnvVarGlobalPrefix, suffix))
+	}
+	return v
+}
+
+// CompDebug prints the specified string to the same file as where the
+// completion script prints its logs, BASH_COMP_DEBUG_FILE if it is set.
+// Such logs are only printed when the user has set the environment
+// variable BASH_COMP_DEBUG_FILE to the path of some file to be used.
+func CompDebug(msg string, printToStdErr bool) {
+	msg = fmt.Sprintf("[Debug] %s", msg)
+
+	if path := os.Getenv("BASH_COMP_DEBUG_FILE"); path != "" {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err == nil {
+			defer f.Close()
+			WriteStringAndCheck(f, msg)
+		}
+	}
+
+	if printToStdErr {
+		// Must print to stderr for this not to be read by the completion script.
+		fmt.Fprint(os.Stderr, msg)
+	}
+}
+
+// CompDebugln prints the specified string with a newline at the end
+// to the same file as where the completion script prints its logs.
+func CompDebugln(msg string, printToStdErr bool) {
+	CompDebug(fmt.Sprintf("%s\n", msg), printToStdErr)
+}
+
+// CompError prints the specified completion message to stderr.
+func CompError(msg string) {
+	msg = fmt.Sprintf("[Error] %s", msg)
+	CompDebug(msg, true)
+}
+
+// CompErrorln prints the specified completion message to stderr with a newline at the end.
+func CompErrorln(msg string) {
+	CompError(fmt.Sprintf("%s\n", msg))
+}
+
 func NoFileCompletions(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
 	return nil, ShellCompDirectiveNoFileComp
 }
@@ -128,7 +197,7 @@ func (c *Command) initCompletionCmd(args []string) {
 		DisableFlagsInUseLine: true,
 		Hidden:                true,
 		DisableFlagParsing:    true,
-		Args:                  MinimumArgs(1),
+		Args:                  MinimumNArgs(1),
 		Short:                 "Request shell completion choices for the specified command-line",
 		Long: fmt.Sprintf("%[2]s is a special command that is used by the shell completion logic\n%[1]s",
 			"to request completion choices for the specified command-line.", ShellCompRequestCmd),
@@ -140,7 +209,7 @@ func (c *Command) initCompletionCmd(args []string) {
 			noDescriptions := cmd.CalledAs() == ShellCompNoDescRequestCmd
 			if !noDescriptions {
 				if doDescriptions, err := strconv.ParseBool(getEnvConfig(cmd, configEnvVarSuffixDescription)); err == nil {
-					noDescription = !doDescriptions
+					noDescriptions = !doDescriptions
 				}
 			}
 			noActiveHelp := GetActiveHelpConfig(finalCmd) == activeHelpGlobalDisable
@@ -185,7 +254,7 @@ func (c *Command) getCompletions(args []string) (*Command, []string, ShellCompDi
 		finalCmd, finalArgs, err = c.Root().Traverse(trimmeArgs)
 	} else {
 		rootCmd := c.Root()
-		if len(rootCmd.Commands() == 1) {
+		if len(rootCmd.Commands()) == 1 {
 			rootCmd.RemoveCommand(c)
 		}
 
@@ -197,11 +266,11 @@ func (c *Command) getCompletions(args []string) (*Command, []string, ShellCompDi
 	finalCmd.ctx = c.ctx
 
 	if !finalCmd.DisableFlagParsing {
-		finalCmd.InitDefaultHelpCmd()
+		finalCmd.InitDefaultHelpFlag()
 		finalCmd.InitDefaultVersionFlag()
 	}
 
-	final, finalArgs, toComplete, flagErr := checkIfFlagCompletion(finalCmd, finalArgs, toComplete)
+	flag, finalArgs, toComplete, flagErr := checkIfFlagCompletion(finalCmd, finalArgs, toComplete)
 
 	flagCompletion := true
 	_ = finalCmd.ParseFlags(append(finalArgs, "--"))
@@ -229,5 +298,433 @@ func (c *Command) getCompletions(args []string) (*Command, []string, ShellCompDi
 		finalArgs = finalCmd.Flags().Args()
 	}
 
-	
-}
\ No newline at end of file
+	if flag != nil && flagCompletion {
+		if validExts, present := flag.Annotations[BashCompFilenameExt]; present && len(validExts) != 0 {
+			return finalCmd, validExts, ShellCompDirectiveFilterFileExt, nil
+		}
+		if subDir, present := flag.Annotations[BashCompSubdirsInDir]; present {
+			if len(subDir) == 1 {
+				return finalCmd, subDir, ShellCompDirectiveFilterDirs, nil
+			}
+			return finalCmd, []string{}, ShellCompDirectiveFilterDirs, nil
+		}
+	}
+
+	var completions []string
+	var directive ShellCompDirective
+
+	restoreFlags := finalCmd.enforceFlagGroupsForCompletion()
+	defer restoreFlags()
+
+	// As soon as an argument starts with a '-' we know it is a flag name,
+	// even if the name is not complete yet.
+	if flag == nil && len(toComplete) > 0 && toComplete[0] == '-' && !strings.Contains(toComplete, "=") && flagCompletion {
+		completions = completeRequireFlags(finalCmd, toComplete)
+
+		if len(completions) == 0 {
+			doCompleteFlags := func(flag *pflag.Flag) {
+				if !flag.Changed ||
+					strings.Contains(flag.Value.Type(), "Slice") ||
+					strings.Contains(flag.Value.Type(), "Array") {
+					completions = append(completions, getFlagNameCompletions(flag, toComplete)...)
+				}
+			}
+
+			finalCmd.InheritedFlags().VisitAll(doCompleteFlags)
+			finalCmd.NonInheritedFlags().VisitAll(doCompleteFlags)
+		}
+
+		directive = ShellCompDirectiveNoFileComp
+		if len(completions) == 1 && strings.HasSuffix(completions[0], "=") {
+			directive = ShellCompDirectiveNoSpace
+		}
+
+		if !finalCmd.DisableFlagParsing {
+			return finalCmd, completions, directive, nil
+		}
+	} else {
+		directive = ShellCompDirectiveDefault
+		if flag == nil {
+			foundLocalNonPersistentFlag := false
+			if !finalCmd.Root().TraverseChildren {
+				localNonPersistentFlags := finalCmd.LocalNonPersistentFlags()
+				finalCmd.NonInheritedFlags().VisitAll(func(flag *pflag.Flag) {
+					if localNonPersistentFlags.Lookup(flag.Name) != nil && flag.Changed {
+						foundLocalNonPersistentFlag = true
+					}
+				})
+			}
+
+			if len(finalArgs) == 0 && !foundLocalNonPersistentFlag {
+				for _, subCmd := range finalCmd.Commands() {
+					if subCmd.IsAvailableCommand() || subCmd == finalCmd.helpCommand {
+						if strings.HasPrefix(subCmd.Name(), toComplete) {
+							completions = append(completions, fmt.Sprintf("%s\t%s", subCmd.Name(), subCmd.Short))
+						}
+						directive = ShellCompDirectiveNoFileComp
+					}
+				}
+			}
+
+			completions = append(completions, completeRequireFlags(finalCmd, toComplete)...)
+
+			if len(finalCmd.ValidArgs) > 0 {
+				if len(finalArgs) == 0 {
+					for _, validArg := range finalCmd.ValidArgs {
+						if strings.HasPrefix(validArg, toComplete) {
+							completions = append(completions, validArg)
+						}
+					}
+					if len(completions) > 0 {
+						directive = ShellCompDirectiveNoFileComp
+					}
+
+					if len(completions) == 0 {
+						for _, argAlias := range finalCmd.argAliases() {
+							if strings.HasPrefix(argAlias, toComplete) {
+								completions = append(completions, argAlias)
+							}
+						}
+					}
+				}
+
+				return finalCmd, completions, directive, nil
+			}
+		}
+	}
+
+	var completionFn func(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective)
+	if flag != nil && flagCompletion {
+		flagCompletionMutex.RLock()
+		completionFn = flagCompletionFunctions[flag]
+		flagCompletionMutex.RUnlock()
+	} else if pos := finalCmd.positionalAt(len(finalArgs)); pos != nil && pos.completionFunc() != nil {
+		completionFn = pos.completionFunc()
+	} else {
+		completionFn = finalCmd.ValidArgsFunction
+	}
+	if completionFn != nil {
+		var comps []string
+		comps, directive = completionFn(finalCmd, finalArgs, toComplete)
+		completions = append(completions, comps...)
+	}
+
+	return finalCmd, completions, directive, nil
+}
+
+func helpOrVersionFlagPresent(cmd *Command) bool {
+	if versionFlag := cmd.Flags().Lookup("version"); versionFlag != nil &&
+		len(versionFlag.Annotations[FlagSetByCoBraAnnotation]) > 0 && versionFlag.Changed {
+		return true
+	}
+	if helpFlag := cmd.Flags().Lookup("help"); helpFlag != nil &&
+		len(helpFlag.Annotations[FlagSetByCoBraAnnotation]) > 0 && helpFlag.Changed {
+		return true
+	}
+	return false
+}
+
+func nonCompletableFlag(flag *pflag.Flag) bool {
+	return flag.Hidden || len(flag.Deprecated) > 0
+}
+
+func getFlagNameCompletions(flag *pflag.Flag, toComplete string) []string {
+	if nonCompletableFlag(flag) {
+		return []string{}
+	}
+
+	var completions []string
+	flagName := "--" + flag.Name
+	if strings.HasPrefix(flagName, toComplete) {
+		completions = append(completions, fmt.Sprintf("%s\t%s", flagName, flag.Usage))
+	}
+
+	flagName = "-" + flag.Shorthand
+	if len(flag.Shorthand) > 0 && strings.HasPrefix(flagName, toComplete) {
+		completions = append(completions, fmt.Sprintf("%s\t%s", flagName, flag.Usage))
+	}
+
+	return completions
+}
+
+func completeRequireFlags(finalCmd *Command, toComplete string) []string {
+	var completions []string
+
+	doCompleteRequiredFlags := func(flag *pflag.Flag) {
+		if _, present := flag.Annotations[BashCompOneRequiredFlag]; present {
+			if !flag.Changed {
+				completions = append(completions, getFlagNameCompletions(flag, toComplete)...)
+			}
+		}
+	}
+
+	finalCmd.InheritedFlags().VisitAll(doCompleteRequiredFlags)
+	finalCmd.NonInheritedFlags().VisitAll(doCompleteRequiredFlags)
+
+	return completions
+}
+
+func checkIfFlagCompletion(finalCmd *Command, args []string, lastArg string) (*pflag.Flag, []string, string, error) {
+	if finalCmd.DisableFlagParsing {
+		return nil, args, lastArg, nil
+	}
+
+	var flagName string
+	trimmedArgs := args
+	flagWithEqual := false
+	orgLastArg := lastArg
+
+	if len(lastArg) > 0 && lastArg[0] == '-' {
+		if index := strings.Index(lastArg, "="); index >= 0 {
+			if strings.HasPrefix(lastArg[:index], "--") {
+				flagName = lastArg[2:index]
+			} else {
+				// the value belongs to the last shorthand of -abc=value
+				flagName = lastArg[index-1 : index]
+			}
+			lastArg = lastArg[index+1:]
+			flagWithEqual = true
+		} else {
+			return nil, args, lastArg, nil
+		}
+	}
+
+	if len(flagName) == 0 && len(args) > 0 {
+		prevArg := args[len(args)-1]
+		if isFlagArgs(prevArg) && !strings.Contains(prevArg, "=") {
+			if strings.HasPrefix(prevArg, "--") {
+				flagName = prevArg[2:]
+			} else {
+				flagName = prevArg[len(prevArg)-1:]
+			}
+			// the flag without its value would fail to parse
+			trimmedArgs = args[:len(args)-1]
+		}
+	}
+
+	if len(flagName) == 0 {
+		return nil, trimmedArgs, lastArg, nil
+	}
+
+	flag := findFlag(finalCmd, flagName)
+	if flag == nil {
+		return nil, args, orgLastArg, &flagCompError{subCommand: finalCmd.Name(), flagName: flagName}
+	}
+
+	if !flagWithEqual && len(flag.NoOptDefVal) != 0 {
+		// a flag which takes no value is not followed by its value
+		trimmedArgs = args
+		flag = nil
+	}
+
+	return flag, trimmedArgs, lastArg, nil
+}
+
+func findFlag(cmd *Command, name string) *pflag.Flag {
+	flagSet := cmd.Flags()
+	if len(name) == 1 {
+		if short := flagSet.ShorthandLookup(name); short != nil {
+			name = short.Name
+		} else if short = cmd.InheritedFlags().ShorthandLookup(name); short != nil {
+			name = short.Name
+		} else {
+			return nil
+		}
+	}
+	return cmd.Flag(name)
+}
+
+// AddCompletionShellCmd adds sub-commands to the default "completion" command
+// so that shells which are not supported out of the box can be handled.
+// Each command is expected to write the completion script to its OutOrStdout().
+// A command whose name is already taken by a sub-command of "completion" is
+// not added. The commands may be added before or after the default
+// "completion" command is created.
+func (c *Command) AddCompletionShellCmd(cmds ...*Command) {
+	root := c.Root()
+	root.completionShellCmds = append(root.completionShellCmds, cmds...)
+	if root.defaultCompletionCmd != nil {
+		root.addCompletionShellCmds()
+	}
+}
+
+// addCompletionShellCmds adds the commands of AddCompletionShellCmd which
+// are not there yet to the default completion command of c.
+func (c *Command) addCompletionShellCmds() {
+	completionCmd := c.defaultCompletionCmd
+Loop:
+	for _, shellCmd := range c.completionShellCmds {
+		for _, cmd := range completionCmd.commands {
+			if cmd == shellCmd || cmd.Name() == shellCmd.Name() {
+				continue Loop
+			}
+		}
+		completionCmd.AddCommand(shellCmd)
+	}
+}
+
+// InitDefaultCompletionCmd adds a default 'completion' command to c.
+// This function will do nothing if any of the following is true:
+// 1- the feature has been explicitly disabled by the program,
+// 2- c has no subcommands (to avoid creating one),
+// 3- c already has a 'completion' command provided by the program.
+//
+// If c already has the default 'completion' command, the shells added since
+// by AddCompletionShellCmd are added to it.
+func (c *Command) InitDefaultCompletionCmd() {
+	if c.defaultCompletionCmd != nil {
+		c.addCompletionShellCmds()
+		return
+	}
+	if c.CompletionOptions.DisableDefaultCmd || !c.HasSubCommands() {
+		return
+	}
+
+	for _, cmd := range c.commands {
+		if cmd.Name() == compCmdName || cmd.HasAlias(compCmdName) {
+			// A completion command is already available
+			return
+		}
+	}
+
+	haveNoDescFlag := !c.CompletionOptions.DisableNoDescFlag && !c.CompletionOptions.DisaableDescriptions
+
+	completionCmd := &Command{
+		Use:   compCmdName,
+		Short: "Generate the autocompletion script for the specified shell",
+		Long: fmt.Sprintf(`Generate the autocompletion script for %[1]s for the specified shell.
+See each sub-command's help for details on how to use the generated script.
+`, c.Root().Name()),
+		Args:              NoArgs,
+		ValidArgsFunction: NoFileCompletions,
+		Hidden:            c.CompletionOptions.HiddenDefaultCmd,
+		GroupID:           c.completionCommandGroupID,
+	}
+	c.AddCommand(completionCmd)
+
+	noDesc := c.CompletionOptions.DisaableDescriptions
+	shortDesc := "Generate the autocompletion script for %s"
+	bash := &Command{
+		Use:   "bash",
+		Short: fmt.Sprintf(shortDesc, "bash"),
+		Long: fmt.Sprintf(`Generate the autocompletion script for the bash shell.
+
+This script depends on the 'bash-completion' package.
+If it is not installed already, you can install it via your OS's package manager.
+
+To load completions in your current shell session:
+
+	source <(%[1]s completion bash)
+
+To load completions for every new session, execute once:
+
+#### Linux:
+
+	%[1]s completion bash > /etc/bash_completion.d/%[1]s
+
+#### macOS:
+
+	%[1]s completion bash > $(brew --prefix)/etc/bash_completion.d/%[1]s
+
+You will need to start a new shell for this setup to take effect.
+`, c.Root().Name()),
+		Args:                  NoArgs,
+		DisableFlagsInUseLine: true,
+		ValidArgsFunction:     NoFileCompletions,
+		RunE: func(cmd *Command, args []string) error {
+			return cmd.Root().GenBashCompletionV2(cmd.OutOrStdout(), !noDesc)
+		},
+	}
+
+	zsh := &Command{
+		Use:   "zsh",
+		Short: fmt.Sprintf(shortDesc, "zsh"),
+		Long: fmt.Sprintf(`Generate the autocompletion script for the zsh shell.
+
+If shell completion is not already enabled in your environment you will need
+to enable it.  You can execute the following once:
+
+	echo "autoload -U compinit; compinit" >> ~/.zshrc
+
+To load completions in your current shell session:
+
+	source <(%[1]s completion zsh)
+
+To load completions for every new session, execute once:
+
+#### Linux:
+
+	%[1]s completion zsh > "${fpath[1]}/_%[1]s"
+
+#### macOS:
+
+	%[1]s completion zsh > $(brew --prefix)/share/zsh/site-functions/_%[1]s
+
+You will need to start a new shell for this setup to take effect.
+`, c.Root().Name()),
+		Args:              NoArgs,
+		ValidArgsFunction: NoFileCompletions,
+		RunE: func(cmd *Command, args []string) error {
+			if noDesc {
+				return cmd.Root().GenZshCompletionNoDesc(cmd.OutOrStdout())
+			}
+			return cmd.Root().GenZshCompletion(cmd.OutOrStdout())
+		},
+	}
+
+	fish := &Command{
+		Use:   "fish",
+		Short: fmt.Sprintf(shortDesc, "fish"),
+		Long: fmt.Sprintf(`Generate the autocompletion script for the fish shell.
+
+To load completions in your current shell session:
+
+	%[1]s completion fish | source
+
+To load completions for every new session, execute once:
+
+	%[1]s completion fish > ~/.config/fish/completions/%[1]s.fish
+
+You will need to start a new shell for this setup to take effect.
+`, c.Root().Name()),
+		Args:              NoArgs,
+		ValidArgsFunction: NoFileCompletions,
+		RunE: func(cmd *Command, args []string) error {
+			return cmd.Root().GenFishCompletion(cmd.OutOrStdout(), !noDesc)
+		},
+	}
+
+	powershell := &Command{
+		Use:   "powershell",
+		Short: fmt.Sprintf(shortDesc, "powershell"),
+		Long: fmt.Sprintf(`Generate the autocompletion script for powershell.
+
+To load completions in your current shell session:
+
+	%[1]s completion powershell | Out-String | Invoke-Expression
+
+To load completions for every new session, add the output of the above command
+to your powershell profile.
+`, c.Root().Name()),
+		Args:              NoArgs,
+		ValidArgsFunction: NoFileCompletions,
+		RunE: func(cmd *Command, args []string) error {
+			if noDesc {
+				return cmd.Root().GenPowerShellCompletion(cmd.OutOrStdout())
+			}
+			return cmd.Root().GenPowerShellCompletionWithDesc(cmd.OutOrStdout())
+		},
+	}
+
+	if haveNoDescFlag {
+		bash.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, compCmdNoDescFlagDesc)
+		zsh.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, compCmdNoDescFlagDesc)
+		fish.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, compCmdNoDescFlagDesc)
+		powershell.Flags().BoolVar(&noDesc, compCmdNoDescFlagName, compCmdNoDescFlagDefault, compCmdNoDescFlagDesc)
+	}
+
+	completionCmd.AddCommand(bash, zsh, fish, powershell)
+
+	c.defaultCompletionCmd = completionCmd
+	c.addCompletionShellCmds()
+}
@@ -0,0 +1,97 @@
+package cobra
+
+import (
+	"strings"
+	"testing"
+)
+
+func completionShellNames(rootCmd *Command) []string {
+	var names []string
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == compCmdName {
+			for _, shellCmd := range cmd.Commands() {
+				names = append(names, shellCmd.Name())
+			}
+		}
+	}
+	return names
+}
+
+func TestDefaultCompletionCmd(t *testing.T) {
+	rootCmd := &Command{Use: "prog", Run: emptyRun}
+	rootCmd.AddCommand(&Command{Use: "child", Run: emptyRun})
+
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		out, err := executeCommand(rootCmd, compCmdName, shell)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %v", shell, err)
+		}
+		if !strings.Contains(out, ShellCompRequestCmd+" ") {
+			t.Errorf("Expected the %s script to request completions with descriptions", shell)
+		}
+	}
+	if got := strings.Join(completionShellNames(rootCmd), " "); got != "bash fish powershell zsh" {
+		t.Errorf("Expected shells: bash fish powershell zsh, got: %s", got)
+	}
+
+	out, err := executeCommand(rootCmd, compCmdName, "bash", "--"+compCmdNoDescFlagName)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(out, ShellCompNoDescRequestCmd) {
+		t.Errorf("Expected the bash script to request completions without descriptions")
+	}
+}
+
+func TestDefaultCompletionCmdDisabled(t *testing.T) {
+	rootCmd := &Command{Use: "prog", Run: emptyRun, CompletionOptions: CompletionOptions{DisableDefaultCmd: true}}
+	rootCmd.AddCommand(&Command{Use: "child", Run: emptyRun})
+
+	if _, err := executeCommand(rootCmd, "child"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, cmd := range rootCmd.Commands() {
+		if cmd.Name() == compCmdName {
+			t.Error("Expected no completion command")
+		}
+	}
+}
+
+func TestAddCompletionShellCmd(t *testing.T) {
+	rootCmd := &Command{Use: "prog", Run: emptyRun}
+	rootCmd.AddCommand(&Command{Use: "child", Run: emptyRun})
+	nushell := &Command{
+		Use: "nushell",
+		Run: func(cmd *Command, args []string) { cmd.Print("nushell script") },
+	}
+	rootCmd.AddCompletionShellCmd(nushell)
+
+	out, err := executeCommand(rootCmd, compCmdName, "nushell")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out != "nushell script" {
+		t.Errorf("Expected output: nushell script, got: %q", out)
+	}
+	if nushell.Flags().Lookup(compCmdNoDescFlagName) != nil {
+		t.Errorf("Expected no --%s flag on a shell command of the program", compCmdNoDescFlagName)
+	}
+
+	// Shells added after the completion command exists are added to it,
+	// and the same shell is only added once.
+	elvish := &Command{Use: "elvish", Run: emptyRun}
+	rootCmd.AddCompletionShellCmd(elvish, &Command{Use: "nushell", Run: emptyRun}, &Command{Use: "bash", Run: emptyRun})
+	rootCmd.AddCompletionShellCmd(elvish)
+	if _, err := executeCommand(rootCmd, compCmdName, "elvish"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rootCmd.InitDefaultCompletionCmd()
+
+	expected := "bash elvish fish nushell powershell zsh"
+	if got := strings.Join(completionShellNames(rootCmd), " "); got != expected {
+		t.Errorf("Expected shells: %s, got: %s", expected, got)
+	}
+	if out, err := executeCommand(rootCmd, compCmdName, "nushell"); err != nil || out != "nushell script" {
+		t.Errorf("Expected the first nushell command to be kept, got: %q, error: %v", out, err)
+	}
+}
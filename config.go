@@ -0,0 +1,249 @@
+package cobra
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// Values of the FlagValueSourceAnnotation of a flag, from the lowest to the highest precedence.
+// A flag is only prompted for when it has no value from the other sources.
+const (
+	FlagSourceDefault = "default"
+	FlagSourceConfig  = "config"
+	FlagSourceEnv     = "env"
+	FlagSourceFlag    = "flag"
+	FlagSourcePrompt  = "prompt"
+)
+
+// configSource locates the config file of a command tree.
+type configSource struct {
+	flagName    string
+	searchPaths []string
+}
+
+// BindFlagsToConfig makes the flags of c and all its descendants fall back to
+// the values of a config file when they are set neither on the command line
+// nor in the environment.
+//
+// The file is the value of the flag flagName when it is set, which must then
+// exist. Otherwise the first existing file of the flag default and searchPaths
+// is used, and no file at all is not an error. The format is chosen by the
+// extension of the file: .json, .yaml, .yml or .toml.
+//
+// Values are looked up by command path relative to c, so that the table
+// [server.start] holds the flags of "server start". An inherited flag is also
+// looked up in the tables of the parents, up to the command defining it.
+// A table is never the value of a flag: when a flag is named like a table,
+// as "server" above, the table is taken as the one of the command and the
+// flag is looked up further up.
+//
+// Like the values from the environment, the values from the config file do
+// not mark the flags as changed. They satisfy MarkFlagRequired, but the flag
+// groups and requirements only see the flags given on the command line.
+func (c *Command) BindFlagsToConfig(flagName string, searchPaths ...string) {
+	c.config = &configSource{flagName: flagName, searchPaths: searchPaths}
+}
+
+// configBinder returns the nearest command, c included, on which
+// BindFlagsToConfig was called, or nil if there is none.
+func (c *Command) configBinder() *Command {
+	for p := c; p != nil; p = p.Parent() {
+		if p.config != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+// relativePath returns the names of the commands from below ancestor down to c.
+// It returns false if ancestor is not c nor one of its parents.
+func (c *Command) relativePath(ancestor *Command) ([]string, bool) {
+	var path []string
+	for p := c; p != ancestor; p = p.Parent() {
+		if p == nil {
+			return nil, false
+		}
+		path = append([]string{p.Name()}, path...)
+	}
+	return path, true
+}
+
+// FlagSource returns where the value of the flag called name came from, one of
+// FlagSourceDefault, FlagSourceConfig, FlagSourceEnv, FlagSourceFlag or FlagSourcePrompt.
+// It returns "" if there is no such flag or the command was not executed.
+func (c *Command) FlagSource(name string) string {
+	f := c.Flags().Lookup(name)
+	if f == nil || len(f.Annotations[FlagValueSourceAnnotation]) == 0 {
+		return ""
+	}
+	return f.Annotations[FlagValueSourceAnnotation][0]
+}
+
+func setFlagSource(flags *flag.FlagSet, name, source string) {
+	_ = flags.SetAnnotation(name, FlagValueSourceAnnotation, []string{source})
+}
+
+// flagHasValue reports whether f was given on the command line or when
+// prompted, or was set from the environment or a config file.
+func flagHasValue(f *flag.Flag) bool {
+	if f.Changed {
+		return true
+	}
+	source := f.Annotations[FlagValueSourceAnnotation]
+	return len(source) > 0 && (source[0] == FlagSourceEnv || source[0] == FlagSourceConfig)
+}
+
+// markFlagSources records the flags given on the command line, and
+// the others as having their default value.
+func (c *Command) markFlagSources() {
+	flags := c.Flags()
+	flags.VisitAll(func(f *flag.Flag) {
+		if f.Changed {
+			setFlagSource(flags, f.Name, FlagSourceFlag)
+		} else {
+			setFlagSource(flags, f.Name, FlagSourceDefault)
+		}
+	})
+}
+
+// path returns the config file to read, or "" if there is none.
+func (s *configSource) path(flags *flag.FlagSet) (string, error) {
+	var candidates []string
+	if f := flags.Lookup(s.flagName); f != nil && f.Value.String() != "" {
+		if flagHasValue(f) {
+			return f.Value.String(), nil
+		}
+		candidates = append(candidates, f.Value.String())
+	}
+	candidates = append(candidates, s.searchPaths...)
+
+	for _, path := range candidates {
+		_, err := os.Stat(path)
+		if err == nil {
+			return path, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// readConfigFile decodes the config file at path into nested tables.
+func readConfigFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]interface{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		values, err = decodeJSONConfig(data)
+	case ".yaml", ".yml":
+		values, err = decodeYAMLConfig(data)
+	case ".toml":
+		values, err = decodeTOMLConfig(data)
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+	return values, nil
+}
+
+// configValue returns the value of f in the config file, looking in the
+// table of c first and then in the tables of its parents up to the command
+// defining f.
+func (c *Command) configValue(binder *Command, values map[string]interface{}, f *flag.Flag) (interface{}, bool) {
+	owner := c.flagOwner(f)
+	for p := c; p != nil; p = p.Parent() {
+		if path, ok := p.relativePath(binder); ok {
+			if table, ok := configTable(values, path); ok {
+				value, found := table[f.Name]
+				if _, isTable := value.(map[string]interface{}); found && value != nil && !isTable {
+					return value, true
+				}
+			}
+		}
+		if p == owner || p == binder {
+			break
+		}
+	}
+	return nil, false
+}
+
+func configTable(values map[string]interface{}, path []string) (map[string]interface{}, bool) {
+	table := values
+	for _, name := range path {
+		next, ok := table[name].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		table = next
+	}
+	return table, true
+}
+
+// setFlagsFromConfig sets every flag which was set neither on the command
+// line nor in the environment from the config file, if it has a value for it.
+// A config file which cannot be read or holds an invalid value is a FlagParseError.
+func (c *Command) setFlagsFromConfig() error {
+	if c.DisableFlagParsing {
+		return nil
+	}
+	binder := c.configBinder()
+	if binder == nil {
+		return nil
+	}
+
+	flags := c.Flags()
+	path, err := binder.config.path(flags)
+	if err != nil {
+		return &FlagParseError{Err: err}
+	}
+	if path == "" {
+		return nil
+	}
+	values, err := readConfigFile(path)
+	if err != nil {
+		return &FlagParseError{Err: err}
+	}
+
+	flags.VisitAll(func(f *flag.Flag) {
+		if err != nil || flagHasValue(f) || f.Name == binder.config.flagName || f.Annotations[FlagSetByCoBraAnnotation] != nil {
+			return
+		}
+		value, found := c.configValue(binder, values, f)
+		if !found {
+			return
+		}
+		if setErr := setFlagFromConfig(f, value); setErr != nil {
+			err = &FlagParseError{Err: fmt.Errorf("invalid value for flag --%s in config file %s: %v", f.Name, path, setErr)}
+			return
+		}
+		setFlagSource(flags, f.Name, FlagSourceConfig)
+	})
+	return err
+}
+
+// setFlagFromConfig sets f to value without marking it as changed.
+func setFlagFromConfig(f *flag.Flag, value interface{}) error {
+	items, ok := value.([]interface{})
+	if !ok {
+		return setFlagValue(f, fmt.Sprint(value))
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		values = append(values, fmt.Sprint(item))
+	}
+	if sv, ok := f.Value.(flag.SliceValue); ok {
+		return sv.Replace(values)
+	}
+	return setFlagValue(f, strings.Join(values, ","))
+}
@@ -0,0 +1,39 @@
+package cobra
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// The config decoders produce nested tables of map[string]interface{}.
+// Values are only ever used to set flags, by their string form, so scalars
+// keep the type of their decoder, lists are []interface{} and null is nil.
+
+func decodeJSONConfig(data []byte) (map[string]interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var values map[string]interface{}
+	if err := dec.Decode(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func decodeYAMLConfig(data []byte) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func decodeTOMLConfig(data []byte) (map[string]interface{}, error) {
+	var values map[string]interface{}
+	if _, err := toml.Decode(string(data), &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
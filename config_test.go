@@ -0,0 +1,146 @@
+package cobra
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"config.json", `{"name": "it's", "tags": ["a", "b"], "port": 8080, "sub": {"verbose": true}}`},
+		{"config.yaml", "name: it's # a comment\ntags: [a, b]\nport: 8080\nsub:\n  verbose: true\n"},
+		{"config.toml", "name = \"it's\" # a comment\ntags = ['a', 'b']\nport = 8080\n\n[sub]\nverbose = true\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rootCmd := &Command{Use: "root"}
+			rootCmd.PersistentFlags().String("config", "", "")
+			rootCmd.PersistentFlags().String("name", "", "")
+			rootCmd.PersistentFlags().StringSlice("tags", nil, "")
+			rootCmd.PersistentFlags().Int("port", 0, "")
+			subCmd := &Command{Use: "sub", Run: emptyRun}
+			subCmd.Flags().Bool("verbose", false, "")
+			rootCmd.AddCommand(subCmd)
+			rootCmd.BindFlagsToConfig("config")
+
+			path := writeConfigFile(t, tc.name, tc.content)
+			if _, err := executeCommand(rootCmd, "sub", "--config", path); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if name, _ := subCmd.Flags().GetString("name"); name != "it's" {
+				t.Errorf("Expected name %q, got %q", "it's", name)
+			}
+			if tags, _ := subCmd.Flags().GetStringSlice("tags"); !reflect.DeepEqual(tags, []string{"a", "b"}) {
+				t.Errorf("Expected tags [a b], got %v", tags)
+			}
+			if port, _ := subCmd.Flags().GetInt("port"); port != 8080 {
+				t.Errorf("Expected port 8080, got %d", port)
+			}
+			if verbose, _ := subCmd.Flags().GetBool("verbose"); !verbose {
+				t.Error("Expected verbose to be set")
+			}
+			if source := subCmd.FlagSource("port"); source != FlagSourceConfig {
+				t.Errorf("Expected source %q, got %q", FlagSourceConfig, source)
+			}
+		})
+	}
+}
+
+func TestConfigErrorsAreFlagErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"invalid value", `{"port": "eighty"}`},
+		{"invalid file", `{"port": `},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rootCmd := &Command{Use: "root", Run: emptyRun}
+			rootCmd.Flags().String("config", "", "")
+			rootCmd.Flags().Int("port", 0, "")
+			rootCmd.BindFlagsToConfig("config")
+
+			var flagErr error
+			rootCmd.SetFlagErrorFunc(func(c *Command, err error) error {
+				flagErr = err
+				return err
+			})
+
+			path := writeConfigFile(t, "config.json", tc.content)
+			_, err := executeCommand(rootCmd, "--config", path)
+			var parseErr *FlagParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Expected a FlagParseError, got %T: %v", err, err)
+			}
+			if flagErr != err {
+				t.Errorf("Expected the error to go through the FlagErrorFunc, got %v", flagErr)
+			}
+		})
+	}
+}
+
+func TestConfigFlagsIgnoredByFlagGroups(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.Flags().String("config", "", "")
+	rootCmd.Flags().Bool("json", false, "")
+	rootCmd.Flags().Bool("yaml", false, "")
+	rootCmd.MarkFlagsMutuallyExclusive("json", "yaml")
+	rootCmd.BindFlagsToConfig("config")
+
+	path := writeConfigFile(t, "config.json", `{"json": true}`)
+	if _, err := executeCommand(rootCmd, "--config", path, "--yaml"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if rootCmd.Flags().Changed("json") {
+		t.Error("Expected json not to be changed")
+	}
+	if yaml, _ := rootCmd.Flags().GetBool("yaml"); !yaml {
+		t.Error("Expected yaml to be set")
+	}
+	if _, err := executeCommand(rootCmd, "--config", path, "--json", "--yaml"); err == nil {
+		t.Error("Expected an error for --json with --yaml")
+	}
+}
+
+func TestConfigFlagNamedLikeTable(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.PersistentFlags().String("config", "", "")
+	rootCmd.PersistentFlags().String("server", "default", "")
+	serverCmd := &Command{Use: "server", Run: emptyRun}
+	serverCmd.Flags().Int("port", 0, "")
+	rootCmd.AddCommand(serverCmd)
+	rootCmd.BindFlagsToConfig("config")
+
+	path := writeConfigFile(t, "config.json", `{"server": {"port": 8080}}`)
+	for _, args := range [][]string{{"--config", path}, {"server", "--config", path}} {
+		if _, err := executeCommand(rootCmd, args...); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", args, err)
+		}
+	}
+
+	if server, _ := rootCmd.Flags().GetString("server"); server != "default" {
+		t.Errorf("Expected server %q, got %q", "default", server)
+	}
+	if port, _ := serverCmd.Flags().GetInt("port"); port != 8080 {
+		t.Errorf("Expected port 8080, got %d", port)
+	}
+}
@@ -0,0 +1,159 @@
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cobra"
+
+	"github.com/spf13/pflag"
+)
+
+const asciidocExtension = ".adoc"
+
+// asciidocCellEscape escapes the cell separator of AsciiDoc tables.
+func asciidocCellEscape(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func printFlagsAsciiDoc(buf *bytes.Buffer, cmd *cobra.Command, title string, flags *pflag.FlagSet) {
+	rows := documentedFlags(flags)
+	if len(rows) == 0 {
+		return
+	}
+
+	buf.WriteString("== " + title + "\n\n")
+	buf.WriteString("[cols=\"2,1,1,4\",options=\"header\"]\n")
+	buf.WriteString("|===\n")
+	buf.WriteString("|Flag |Type |Default |Description\n")
+	for _, flag := range rows {
+		names := fmt.Sprintf("`--%s`", flag.Name)
+		if len(flag.Shorthand) > 0 && len(flag.ShorthandDeprecated) == 0 {
+			names = fmt.Sprintf("`-%s`, %s", flag.Shorthand, names)
+		}
+		defValue := ""
+		if flag.DefValue != "" {
+			defValue = fmt.Sprintf("`+%s+`", flag.DefValue)
+		}
+		// the lines of the usage are kept with hard line breaks
+		usage := strings.ReplaceAll(asciidocCellEscape(flagUsage(cmd, flag)), "\n", " +\n")
+		buf.WriteString("\n")
+		buf.WriteString(fmt.Sprintf("|%s\n", names))
+		buf.WriteString(fmt.Sprintf("|%s\n", flag.Value.Type()))
+		buf.WriteString(fmt.Sprintf("|%s\n", asciidocCellEscape(defValue)))
+		buf.WriteString(fmt.Sprintf("|%s\n", usage))
+	}
+	buf.WriteString("|===\n\n")
+}
+
+func printOptionsAsciiDoc(buf *bytes.Buffer, cmd *cobra.Command) {
+	printFlagsAsciiDoc(buf, cmd, "Options", localFlags(cmd))
+	printFlagsAsciiDoc(buf, cmd, "Options inherited from parent commands", cmd.InheritedFlags())
+}
+
+// defaultAsciiDocLinkHandler for default AsciiDoc cross references
+func defaultAsciiDocLinkHandler(name, ref string) string {
+	return fmt.Sprintf("xref:%s%s[%s]", ref, asciidocExtension, name)
+}
+
+// GenAsciiDoc creates AsciiDoc output.
+func GenAsciiDoc(cmd *cobra.Command, w io.Writer) error {
+	return GenAsciiDocCustom(cmd, w, defaultAsciiDocLinkHandler)
+}
+
+// GenAsciiDocCustom creates custom AsciiDoc output.
+func GenAsciiDocCustom(cmd *cobra.Command, w io.Writer, linkHandler func(string, string) string) error {
+	buf := new(bytes.Buffer)
+	name := cmd.CommandPath()
+	ref := strings.ReplaceAll(name, " ", "_")
+
+	buf.WriteString("[[" + ref + "]]\n")
+	buf.WriteString("= " + name + "\n\n")
+	buf.WriteString(cmd.Short + "\n\n")
+	if len(cmd.Long) > 0 {
+		buf.WriteString("== Synopsis\n\n")
+		buf.WriteString(cmd.Long + "\n\n")
+	}
+
+	if cmd.Runnable() {
+		buf.WriteString(fmt.Sprintf("[source]\n----\n%s\n----\n\n", useLine(cmd)))
+	}
+
+	if len(cmd.Aliases) > 0 {
+		buf.WriteString("== Aliases\n\n")
+		buf.WriteString(fmt.Sprintf("[source]\n----\n%s\n----\n\n", cmd.NameAndAliases()))
+	}
+
+	if cmd.HasExample() {
+		buf.WriteString("== Examples\n\n")
+		buf.WriteString(fmt.Sprintf("[source]\n----\n%s\n----\n\n", strings.TrimRight(cmd.Example, "\n")))
+	}
+
+	printOptionsAsciiDoc(buf, cmd)
+
+	if hasSeeAlso(cmd) {
+		buf.WriteString("== SEE ALSO\n\n")
+		if cmd.HasParent() {
+			parent := cmd.Parent()
+			pname := parent.CommandPath()
+			ref = strings.ReplaceAll(pname, " ", "_")
+			buf.WriteString(fmt.Sprintf("* %s - %s\n", linkHandler(pname, ref), parent.Short))
+			buf.WriteString("\n")
+		}
+
+		for _, group := range groupedChildren(cmd) {
+			if group.Title != "" {
+				buf.WriteString("=== " + group.Title + "\n\n")
+			}
+			for _, child := range group.Commands {
+				cname := name + " " + child.Name()
+				ref = strings.ReplaceAll(cname, " ", "_")
+				buf.WriteString(fmt.Sprintf("* %s - %s\n", linkHandler(cname, ref), child.Short))
+			}
+			buf.WriteString("\n")
+		}
+	}
+	if !autoGenTagDisabled(cmd) {
+		buf.WriteString("_" + autoGenTag + " on " + time.Now().Format("2-Jan-2006") + "_\n")
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// GenAsciiDocTree will generate an AsciiDoc page for this command and all
+// descendants in the directory given.
+func GenAsciiDocTree(cmd *cobra.Command, dir string) error {
+	emptyStr := func(s string) string { return "" }
+	return GenAsciiDocTreeCustom(cmd, dir, emptyStr, defaultAsciiDocLinkHandler)
+}
+
+// GenAsciiDocTreeCustom is the same as GenAsciiDocTree, but
+// with custom filePrepender and linkHandler.
+func GenAsciiDocTreeCustom(cmd *cobra.Command, dir string, filePrepender func(string) string, linkHandler func(string, string) string) error {
+	for _, c := range cmd.Commands() {
+		if !documented(c) {
+			continue
+		}
+		if err := GenAsciiDocTreeCustom(c, dir, filePrepender, linkHandler); err != nil {
+			return err
+		}
+	}
+
+	basename := strings.ReplaceAll(cmd.CommandPath(), " ", "_") + asciidocExtension
+	filename := filepath.Join(dir, basename)
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.WriteString(f, filePrepender(filename)); err != nil {
+		return err
+	}
+	return GenAsciiDocCustom(cmd, f, linkHandler)
+}
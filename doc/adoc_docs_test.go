@@ -0,0 +1,63 @@
+package doc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cobra"
+)
+
+func TestGenAsciiDoc(t *testing.T) {
+	rootCmd := newTestTree()
+	rootCmd.DisableAutoGenTag = true
+
+	tests := []struct {
+		path   []string
+		golden string
+	}{
+		{nil, "root.adoc.golden"},
+		{[]string{"echo"}, "root_echo.adoc.golden"},
+	}
+	for _, tc := range tests {
+		buf := new(bytes.Buffer)
+		if err := GenAsciiDoc(findCommand(t, rootCmd, tc.path...), buf); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		checkGolden(t, tc.golden, buf.Bytes())
+	}
+}
+
+func TestGenAsciiDocAutoGenTag(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := GenAsciiDoc(findCommand(t, newTestTree(), "echo"), buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), autoGenTag+" on ") {
+		t.Errorf("Expected %q in:\n%s", autoGenTag, buf.String())
+	}
+}
+
+func TestGenAsciiDocDoesNotChangeTree(t *testing.T) {
+	checkUnchanged(t, func(cmd *cobra.Command) error {
+		return GenAsciiDoc(cmd, new(bytes.Buffer))
+	})
+}
+
+func TestGenAsciiDocTree(t *testing.T) {
+	dir := t.TempDir()
+	if err := GenAsciiDocTree(newTestTree(), dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"root.adoc", "root_echo.adoc", "root_echo_times.adoc", "root_print.adoc"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be generated: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "root_deprecated.adoc")); err == nil {
+		t.Error("Expected no page for a deprecated command")
+	}
+}
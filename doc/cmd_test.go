@@ -0,0 +1,120 @@
+package doc
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"cobra"
+)
+
+var update = flag.Bool("update", false, "update the golden files of the generators")
+
+func emptyRun(*cobra.Command, []string) {}
+
+// newTestTree returns a command tree using the features the generators
+// document: groups, aliases, examples, persistent, hidden and deprecated
+// flags and commands, environment variables and flag requirements.
+func newTestTree() *cobra.Command {
+	rootCmd := &cobra.Command{
+		Use:   "root",
+		Short: "Root short description",
+		Long:  "Root long description",
+		Run:   emptyRun,
+	}
+	rootCmd.PersistentFlags().StringP("rootflag", "r", "two", "a root persistent flag")
+	rootCmd.PersistentFlags().String("secret", "", "a hidden persistent flag")
+	_ = rootCmd.PersistentFlags().MarkHidden("secret")
+	rootCmd.BindFlagsToEnv("app")
+	rootCmd.AddGroup(&cobra.Group{ID: "core", Title: "Core Commands:"})
+
+	echoCmd := &cobra.Command{
+		Use:     "echo [string to echo]",
+		Aliases: []string{"say"},
+		Short:   "Echo anything to the screen",
+		Long:    "an utterly useless command for testing",
+		Example: "Just run root echo",
+		GroupID: "core",
+		Run:     emptyRun,
+	}
+	echoCmd.Flags().IntP("times", "t", 1, "number of `times` to echo")
+	echoCmd.Flags().String("format", "text", "output format")
+	echoCmd.Flags().String("out", "", "output file")
+	echoCmd.Flags().Bool("boolone", true, "help message for flag boolone")
+	echoCmd.Flags().Bool("old", false, "a deprecated flag")
+	_ = echoCmd.Flags().MarkDeprecated("old", "use --format")
+	echoCmd.MarkFlagRequires("out", "format")
+
+	timesCmd := &cobra.Command{
+		Use:   "times [# times] [string to echo]",
+		Short: "Echo anything to the screen more times",
+		Run:   emptyRun,
+	}
+	echoCmd.AddCommand(timesCmd)
+
+	printCmd := &cobra.Command{
+		Use:   "print [string to print]",
+		Short: "Print anything to the screen",
+		Run:   emptyRun,
+	}
+	deprecatedCmd := &cobra.Command{
+		Use:        "deprecated",
+		Short:      "A command which is deprecated",
+		Deprecated: "use print",
+		Run:        emptyRun,
+	}
+	rootCmd.AddCommand(printCmd, echoCmd, deprecatedCmd)
+	return rootCmd
+}
+
+// findCommand returns the command of rootCmd at the path given by args.
+func findCommand(t *testing.T, rootCmd *cobra.Command, args ...string) *cobra.Command {
+	t.Helper()
+	cmd, _, err := rootCmd.Find(args)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return cmd
+}
+
+// checkGolden compares got to the golden file name in testdata, or updates
+// the file if the tests run with -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	golden := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Output differs from %s, got:\n%s", golden, got)
+	}
+}
+
+// checkUnchanged fails if generating the docs of the echo command with gen
+// changes the tree. DisableAutoGenTag is set on the root, as it is inherited.
+func checkUnchanged(t *testing.T, gen func(*cobra.Command) error) {
+	t.Helper()
+	rootCmd := newTestTree()
+	rootCmd.DisableAutoGenTag = true
+	before := rootCmd.Schema()
+	cmd := findCommand(t, rootCmd, "echo")
+	if err := gen(cmd); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if after := rootCmd.Schema(); !reflect.DeepEqual(before, after) {
+		t.Errorf("Expected the command tree to be unchanged, before:\n%+v\nafter:\n%+v", before, after)
+	}
+	if cmd.DisableAutoGenTag {
+		t.Error("Expected DisableAutoGenTag of echo to be unchanged")
+	}
+}
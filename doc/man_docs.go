@@ -0,0 +1,238 @@
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"cobra"
+
+	"github.com/spf13/pflag"
+)
+
+// GenManTree will generate a man page for this command and all descendants
+// in the directory given. The header may be nil. This function may not work
+// correctly if your command names have `-` in them. If you have `cmd` with two
+// subcmds, `sub` and `sub-third`, and `sub` has a subcommand called `third`
+// it is undefined which help output will be in the file `cmd-sub-third.1`.
+func GenManTree(cmd *cobra.Command, header *GenManHeader, dir string) error {
+	return GenManTreeFromOpts(cmd, GenManTreeOptions{
+		Header:           header,
+		Path:             dir,
+		CommandSeparator: "-",
+	})
+}
+
+// GenManTreeFromOpts generates a man page for the command and all descendants.
+// The pages are written to the opts.Path directory.
+func GenManTreeFromOpts(cmd *cobra.Command, opts GenManTreeOptions) error {
+	header := opts.Header
+	if header == nil {
+		header = &GenManHeader{}
+	}
+	for _, c := range cmd.Commands() {
+		if !documented(c) {
+			continue
+		}
+		if err := GenManTreeFromOpts(c, opts); err != nil {
+			return err
+		}
+	}
+	section := "1"
+	if header.Section != "" {
+		section = header.Section
+	}
+
+	separator := "_"
+	if opts.CommandSeparator != "" {
+		separator = opts.CommandSeparator
+	}
+	basename := strings.ReplaceAll(cmd.CommandPath(), " ", separator)
+	filename := filepath.Join(opts.Path, basename+"."+section)
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	headerCopy := *header
+	return GenMan(cmd, &headerCopy, f)
+}
+
+// GenManTreeOptions is the options for generating the man pages.
+// Used only in GenManTreeFromOpts.
+type GenManTreeOptions struct {
+	Header           *GenManHeader
+	Path             string
+	CommandSeparator string
+}
+
+// GenManHeader is a lot like the .TH header at the start of man pages. These
+// include the title, section, date, source, and manual. We will use the
+// current time if Date is unset and will use "Auto generated by cobra"
+// if the Source is unset.
+type GenManHeader struct {
+	Title   string
+	Section string
+	Date    *time.Time
+	date    string
+	Source  string
+	Manual  string
+}
+
+// GenMan will generate a man page for the given command and write it to
+// w. The header argument may be nil, however obviously w may not.
+func GenMan(cmd *cobra.Command, header *GenManHeader, w io.Writer) error {
+	if header == nil {
+		header = &GenManHeader{}
+	}
+	if err := fillHeader(header, cmd.CommandPath(), autoGenTagDisabled(cmd)); err != nil {
+		return err
+	}
+
+	b := genMan(cmd, header)
+	_, err := w.Write(b)
+	return err
+}
+
+func fillHeader(header *GenManHeader, name string, disableAutoGen bool) error {
+	if header.Title == "" {
+		header.Title = strings.ToUpper(strings.ReplaceAll(name, " ", "\\-"))
+	}
+	if header.Section == "" {
+		header.Section = "1"
+	}
+	if header.Date == nil {
+		now := time.Now()
+		if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+			unixEpoch, err := strconv.ParseInt(epoch, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid SOURCE_DATE_EPOCH: %v", err)
+			}
+			now = time.Unix(unixEpoch, 0)
+		}
+		header.Date = &now
+	}
+	header.date = header.Date.Format("Jan 2006")
+	if header.Source == "" && !disableAutoGen {
+		header.Source = autoGenTag
+	}
+	return nil
+}
+
+// manEscape escapes the text so that troff does not interpret it.
+func manEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func manPreamble(buf io.StringWriter, header *GenManHeader, cmd *cobra.Command, dashedName string) {
+	description := cmd.Long
+	if len(description) == 0 {
+		description = cmd.Short
+	}
+
+	cobra.WriteStringAndCheck(buf, fmt.Sprintf(`.nh
+.TH "%s" "%s" "%s" "%s" "%s"
+`, header.Title, header.Section, header.date, header.Source, header.Manual))
+	cobra.WriteStringAndCheck(buf, ".SH NAME\n")
+	cobra.WriteStringAndCheck(buf, fmt.Sprintf("%s \\- %s\n", dashedName, manEscape(cmd.Short)))
+	cobra.WriteStringAndCheck(buf, ".SH SYNOPSIS\n")
+	cobra.WriteStringAndCheck(buf, fmt.Sprintf("\\fB%s\\fP\n", manEscape(useLine(cmd))))
+	cobra.WriteStringAndCheck(buf, ".SH DESCRIPTION\n")
+	cobra.WriteStringAndCheck(buf, manEscape(strings.TrimSpace(description))+"\n")
+}
+
+func manPrintFlags(buf io.StringWriter, cmd *cobra.Command, flags *pflag.FlagSet) {
+	for _, flag := range documentedFlags(flags) {
+		format := ""
+		if len(flag.Shorthand) > 0 && len(flag.ShorthandDeprecated) == 0 {
+			format = "\\fB\\-%s\\fP, \\fB\\-\\-%s\\fP"
+		} else {
+			format = "%s\\fB\\-\\-%s\\fP"
+		}
+		if len(flag.NoOptDefVal) > 0 {
+			format += "["
+		}
+		if flag.Value.Type() == "string" {
+			// put quotes on the value
+			format += "=%q"
+		} else {
+			format += "=%s"
+		}
+		if len(flag.NoOptDefVal) > 0 {
+			format += "]"
+		}
+		format += "\n%s\n"
+		cobra.WriteStringAndCheck(buf, ".TP\n")
+		usage := strings.ReplaceAll(manEscape(cmd.FlagUsage(flag)), "\n", "\n.br\n")
+		cobra.WriteStringAndCheck(buf, fmt.Sprintf(format, flag.Shorthand, flag.Name, manEscape(flag.DefValue), usage))
+	}
+}
+
+func manPrintOptions(buf io.StringWriter, command *cobra.Command) {
+	flags := localFlags(command)
+	if flags.HasAvailableFlags() {
+		cobra.WriteStringAndCheck(buf, ".SH OPTIONS\n")
+		manPrintFlags(buf, command, flags)
+	}
+	flags = command.InheritedFlags()
+	if flags.HasAvailableFlags() {
+		cobra.WriteStringAndCheck(buf, ".SH OPTIONS INHERITED FROM PARENT COMMANDS\n")
+		manPrintFlags(buf, command, flags)
+	}
+}
+
+func genMan(cmd *cobra.Command, header *GenManHeader) []byte {
+	// something like `rootcmd-subcmd1-subcmd2`
+	dashCommandName := strings.ReplaceAll(cmd.CommandPath(), " ", "-")
+
+	buf := new(bytes.Buffer)
+
+	manPreamble(buf, header, cmd, dashCommandName)
+	manPrintOptions(buf, cmd)
+	if len(cmd.Aliases) > 0 {
+		cobra.WriteStringAndCheck(buf, ".SH ALIASES\n")
+		cobra.WriteStringAndCheck(buf, manEscape(cmd.NameAndAliases())+"\n")
+	}
+	if len(cmd.Example) > 0 {
+		cobra.WriteStringAndCheck(buf, ".SH EXAMPLE\n")
+		cobra.WriteStringAndCheck(buf, ".PP\n.RS\n.nf\n")
+		cobra.WriteStringAndCheck(buf, manEscape(strings.TrimRight(cmd.Example, "\n"))+"\n")
+		cobra.WriteStringAndCheck(buf, ".fi\n.RE\n")
+	}
+	if hasSeeAlso(cmd) {
+		cobra.WriteStringAndCheck(buf, ".SH SEE ALSO\n")
+		seealsos := make([]string, 0)
+		if cmd.HasParent() {
+			parentPath := cmd.Parent().CommandPath()
+			dashParentPath := strings.ReplaceAll(parentPath, " ", "-")
+			seealso := fmt.Sprintf("\\fB%s(%s)\\fP", dashParentPath, header.Section)
+			seealsos = append(seealsos, seealso)
+		}
+		for _, c := range sortedChildren(cmd) {
+			if !documented(c) {
+				continue
+			}
+			seealso := fmt.Sprintf("\\fB%s\\-%s(%s)\\fP", dashCommandName, c.Name(), header.Section)
+			seealsos = append(seealsos, seealso)
+		}
+		cobra.WriteStringAndCheck(buf, strings.Join(seealsos, ", ")+"\n")
+	}
+	if !autoGenTagDisabled(cmd) {
+		cobra.WriteStringAndCheck(buf, ".SH HISTORY\n")
+		cobra.WriteStringAndCheck(buf, fmt.Sprintf("%s %s\n", header.Date.Format("2-Jan-2006"), autoGenTag))
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,72 @@
+package doc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cobra"
+)
+
+func TestGenMan(t *testing.T) {
+	date := time.Date(2020, time.March, 4, 0, 0, 0, 0, time.UTC)
+	rootCmd := newTestTree()
+
+	tests := []struct {
+		path   []string
+		golden string
+	}{
+		{nil, "root.1.golden"},
+		{[]string{"echo"}, "root-echo.1.golden"},
+	}
+	for _, tc := range tests {
+		buf := new(bytes.Buffer)
+		header := &GenManHeader{Title: "TEST", Section: "1", Date: &date}
+		if err := GenMan(findCommand(t, rootCmd, tc.path...), header, buf); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		checkGolden(t, tc.golden, buf.Bytes())
+	}
+}
+
+func TestGenManNoAutoGenTag(t *testing.T) {
+	rootCmd := newTestTree()
+	rootCmd.DisableAutoGenTag = true
+
+	buf := new(bytes.Buffer)
+	if err := GenMan(findCommand(t, rootCmd, "echo"), nil, buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(buf.String(), autoGenTag) {
+		t.Errorf("Expected no %q in:\n%s", autoGenTag, buf.String())
+	}
+}
+
+func TestGenManDoesNotChangeTree(t *testing.T) {
+	checkUnchanged(t, func(cmd *cobra.Command) error {
+		return GenMan(cmd, nil, new(bytes.Buffer))
+	})
+}
+
+func TestGenManTree(t *testing.T) {
+	dir := t.TempDir()
+	header := &GenManHeader{Section: "2"}
+	if err := GenManTree(newTestTree(), header, dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"root.2", "root-echo.2", "root-echo-times.2", "root-print.2"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be generated: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "root-deprecated.2")); err == nil {
+		t.Error("Expected no page for a deprecated command")
+	}
+	if header.Title != "" {
+		t.Errorf("Expected the header to be unchanged, got title: %q", header.Title)
+	}
+}
@@ -0,0 +1,63 @@
+package doc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cobra"
+)
+
+func TestGenMarkdown(t *testing.T) {
+	rootCmd := newTestTree()
+	rootCmd.DisableAutoGenTag = true
+
+	tests := []struct {
+		path   []string
+		golden string
+	}{
+		{nil, "root.md.golden"},
+		{[]string{"echo"}, "root_echo.md.golden"},
+	}
+	for _, tc := range tests {
+		buf := new(bytes.Buffer)
+		if err := GenMarkdown(findCommand(t, rootCmd, tc.path...), buf); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		checkGolden(t, tc.golden, buf.Bytes())
+	}
+}
+
+func TestGenMarkdownAutoGenTag(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := GenMarkdown(findCommand(t, newTestTree(), "echo"), buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "###### "+autoGenTag+" on ") {
+		t.Errorf("Expected %q in:\n%s", autoGenTag, buf.String())
+	}
+}
+
+func TestGenMarkdownDoesNotChangeTree(t *testing.T) {
+	checkUnchanged(t, func(cmd *cobra.Command) error {
+		return GenMarkdown(cmd, new(bytes.Buffer))
+	})
+}
+
+func TestGenMarkdownTree(t *testing.T) {
+	dir := t.TempDir()
+	if err := GenMarkdownTree(newTestTree(), dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"root.md", "root_echo.md", "root_echo_times.md", "root_print.md"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be generated: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "root_deprecated.md")); err == nil {
+		t.Error("Expected no page for a deprecated command")
+	}
+}
@@ -0,0 +1,194 @@
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cobra"
+
+	"github.com/spf13/pflag"
+)
+
+const restExtension = ".rst"
+
+// restEscape escapes the characters that reStructuredText would otherwise
+// interpret as inline markup.
+func restEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "*", `\*`, "`", "\\`", "|", `\|`, "_", `\_`)
+	return r.Replace(s)
+}
+
+func printFlagsReST(buf *bytes.Buffer, cmd *cobra.Command, title string, flags *pflag.FlagSet) {
+	rows := documentedFlags(flags)
+	if len(rows) == 0 {
+		return
+	}
+
+	buf.WriteString(title + "\n")
+	buf.WriteString(strings.Repeat("~", len(title)) + "\n\n")
+	buf.WriteString(".. list-table::\n")
+	buf.WriteString("   :header-rows: 1\n\n")
+	buf.WriteString("   * - Flag\n     - Type\n     - Default\n     - Description\n")
+	for _, flag := range rows {
+		names := fmt.Sprintf("``--%s``", flag.Name)
+		if len(flag.Shorthand) > 0 && len(flag.ShorthandDeprecated) == 0 {
+			names = fmt.Sprintf("``-%s``, %s", flag.Shorthand, names)
+		}
+		defValue := ""
+		if flag.DefValue != "" {
+			defValue = fmt.Sprintf("``%s``", flag.DefValue)
+		}
+		// a usage of several lines is written as a line block
+		usage := restEscape(flagUsage(cmd, flag))
+		if strings.Contains(usage, "\n") {
+			usage = "| " + strings.ReplaceAll(usage, "\n", "\n       | ")
+		}
+		buf.WriteString(fmt.Sprintf("   * - %s\n", names))
+		buf.WriteString(fmt.Sprintf("     - %s\n", flag.Value.Type()))
+		buf.WriteString(fmt.Sprintf("     - %s\n", defValue))
+		buf.WriteString(fmt.Sprintf("     - %s\n", usage))
+	}
+	buf.WriteString("\n")
+}
+
+func printOptionsReST(buf *bytes.Buffer, cmd *cobra.Command) {
+	printFlagsReST(buf, cmd, "Options", localFlags(cmd))
+	printFlagsReST(buf, cmd, "Options inherited from parent commands", cmd.InheritedFlags())
+}
+
+// defaultLinkHandler for default ReST hyperlink markup
+func defaultLinkHandler(name, ref string) string {
+	return fmt.Sprintf("`%s <%s%s>`_", name, ref, restExtension)
+}
+
+// GenReST creates reStructured Text output.
+func GenReST(cmd *cobra.Command, w io.Writer) error {
+	return GenReSTCustom(cmd, w, defaultLinkHandler)
+}
+
+// GenReSTCustom creates custom reStructured Text output.
+func GenReSTCustom(cmd *cobra.Command, w io.Writer, linkHandler func(string, string) string) error {
+	buf := new(bytes.Buffer)
+	name := cmd.CommandPath()
+
+	short := cmd.Short
+	long := cmd.Long
+	if len(long) == 0 {
+		long = short
+	}
+	ref := strings.ReplaceAll(name, " ", "_")
+
+	buf.WriteString(".. _" + ref + ":\n\n")
+	buf.WriteString(name + "\n")
+	buf.WriteString(strings.Repeat("-", len(name)) + "\n\n")
+	buf.WriteString(short + "\n\n")
+	buf.WriteString("Synopsis\n")
+	buf.WriteString("~~~~~~~~\n\n")
+	buf.WriteString(long + "\n\n")
+
+	if cmd.Runnable() {
+		buf.WriteString(fmt.Sprintf("::\n\n  %s\n\n", useLine(cmd)))
+	}
+
+	if len(cmd.Aliases) > 0 {
+		buf.WriteString("Aliases\n")
+		buf.WriteString("~~~~~~~\n\n")
+		buf.WriteString(fmt.Sprintf("::\n\n  %s\n\n", cmd.NameAndAliases()))
+	}
+
+	if cmd.HasExample() {
+		buf.WriteString("Examples\n")
+		buf.WriteString("~~~~~~~~\n\n")
+		buf.WriteString(fmt.Sprintf("::\n\n%s\n\n", indentString(cmd.Example, "  ")))
+	}
+
+	printOptionsReST(buf, cmd)
+
+	if hasSeeAlso(cmd) {
+		buf.WriteString("SEE ALSO\n")
+		buf.WriteString("~~~~~~~~\n\n")
+		if cmd.HasParent() {
+			parent := cmd.Parent()
+			pname := parent.CommandPath()
+			ref = strings.ReplaceAll(pname, " ", "_")
+			buf.WriteString(fmt.Sprintf("* %s \t - %s\n", linkHandler(pname, ref), parent.Short))
+			buf.WriteString("\n")
+		}
+
+		for _, group := range groupedChildren(cmd) {
+			if group.Title != "" {
+				buf.WriteString(group.Title + "\n")
+				buf.WriteString(strings.Repeat("^", len(group.Title)) + "\n\n")
+			}
+			for _, child := range group.Commands {
+				cname := name + " " + child.Name()
+				ref = strings.ReplaceAll(cname, " ", "_")
+				buf.WriteString(fmt.Sprintf("* %s \t - %s\n", linkHandler(cname, ref), child.Short))
+			}
+			buf.WriteString("\n")
+		}
+	}
+	if !autoGenTagDisabled(cmd) {
+		buf.WriteString("*" + autoGenTag + " on " + time.Now().Format("2-Jan-2006") + "*\n")
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// GenReSTTree will generate a ReST page for this command and all
+// descendants in the directory given.
+// This function may not work correctly if your command names have `-` in them.
+// If you have `cmd` with two subcmds, `sub` and `sub-third`,
+// and `sub` has a subcommand called `third`, it is undefined which
+// help output will be in the file `cmd-sub-third.1`.
+func GenReSTTree(cmd *cobra.Command, dir string) error {
+	emptyStr := func(s string) string { return "" }
+	return GenReSTTreeCustom(cmd, dir, emptyStr, defaultLinkHandler)
+}
+
+// GenReSTTreeCustom is the same as GenReSTTree, but
+// with custom filePrepender and linkHandler.
+func GenReSTTreeCustom(cmd *cobra.Command, dir string, filePrepender func(string) string, linkHandler func(string, string) string) error {
+	for _, c := range cmd.Commands() {
+		if !documented(c) {
+			continue
+		}
+		if err := GenReSTTreeCustom(c, dir, filePrepender, linkHandler); err != nil {
+			return err
+		}
+	}
+
+	basename := strings.ReplaceAll(cmd.CommandPath(), " ", "_") + restExtension
+	filename := filepath.Join(dir, basename)
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.WriteString(f, filePrepender(filename)); err != nil {
+		return err
+	}
+	return GenReSTCustom(cmd, f, linkHandler)
+}
+
+// indentString adapted from: https://github.com/kr/text/blob/main/indent.go
+func indentString(s, p string) string {
+	var res []byte
+	b := []byte(s)
+	prefix := []byte(p)
+	bol := true
+	for _, c := range b {
+		if bol && c != '\n' {
+			res = append(res, prefix...)
+		}
+		res = append(res, c)
+		bol = c == '\n'
+	}
+	return string(res)
+}
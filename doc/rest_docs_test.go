@@ -0,0 +1,63 @@
+package doc
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cobra"
+)
+
+func TestGenReST(t *testing.T) {
+	rootCmd := newTestTree()
+	rootCmd.DisableAutoGenTag = true
+
+	tests := []struct {
+		path   []string
+		golden string
+	}{
+		{nil, "root.rst.golden"},
+		{[]string{"echo"}, "root_echo.rst.golden"},
+	}
+	for _, tc := range tests {
+		buf := new(bytes.Buffer)
+		if err := GenReST(findCommand(t, rootCmd, tc.path...), buf); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		checkGolden(t, tc.golden, buf.Bytes())
+	}
+}
+
+func TestGenReSTAutoGenTag(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := GenReST(findCommand(t, newTestTree(), "echo"), buf); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), autoGenTag+" on ") {
+		t.Errorf("Expected %q in:\n%s", autoGenTag, buf.String())
+	}
+}
+
+func TestGenReSTDoesNotChangeTree(t *testing.T) {
+	checkUnchanged(t, func(cmd *cobra.Command) error {
+		return GenReST(cmd, new(bytes.Buffer))
+	})
+}
+
+func TestGenReSTTree(t *testing.T) {
+	dir := t.TempDir()
+	if err := GenReSTTree(newTestTree(), dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, name := range []string{"root.rst", "root_echo.rst", "root_echo_times.rst", "root_print.rst"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("Expected %s to be generated: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "root_deprecated.rst")); err == nil {
+		t.Error("Expected no page for a deprecated command")
+	}
+}
@@ -0,0 +1,164 @@
+package doc
+
+import (
+	"sort"
+	"strings"
+
+	"cobra"
+
+	"github.com/spf13/pflag"
+)
+
+// autoGenTag is the note the generators add to the pages unless
+// DisableAutoGenTag is set.
+const autoGenTag = "Auto generated by cobra"
+
+// autoGenTagDisabled reports whether DisableAutoGenTag is set on cmd or
+// on one of its parents.
+func autoGenTagDisabled(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.DisableAutoGenTag {
+			return true
+		}
+	}
+	return false
+}
+
+// localFlags returns the local flags of cmd as its help lists them, with
+// the help flag that cobra adds to cmd when it runs. cmd is not modified.
+func localFlags(cmd *cobra.Command) *pflag.FlagSet {
+	flags := pflag.NewFlagSet(cmd.Name(), pflag.ContinueOnError)
+	flags.SortFlags = cmd.LocalFlags().SortFlags
+	flags.AddFlagSet(cmd.LocalFlags())
+	if cmd.Flags().Lookup("help") == nil {
+		shorthand := "h"
+		if cmd.Flags().ShorthandLookup(shorthand) != nil {
+			shorthand = ""
+		}
+		usage := "help for this command"
+		if cmd.Name() != "" {
+			usage = "help for " + cmd.Name()
+		}
+		flags.BoolP("help", shorthand, false, usage)
+		_ = flags.SetAnnotation("help", cobra.FlagSetByCoBraAnnotation, []string{"true"})
+	}
+	return flags
+}
+
+// useLine returns the UseLine of cmd, with the "[flags]" that the help flag
+// of localFlags adds to it.
+func useLine(cmd *cobra.Command) string {
+	line := cmd.UseLine()
+	if !cmd.DisableFlagsInUseLine && !strings.Contains(line, "[flags]") {
+		line += " [flags]"
+	}
+	return line
+}
+
+// flagUsage returns the usage of flag as the help of cmd shows it, with the
+// back quoted name of its value unquoted as pflag does.
+func flagUsage(cmd *cobra.Command, flag *pflag.Flag) string {
+	annotated := *flag
+	annotated.Usage = cmd.FlagUsage(flag)
+	_, usage := pflag.UnquoteUsage(&annotated)
+	return usage
+}
+
+// documented reports whether cmd gets a page of its own and is listed on the
+// page of its parent. All generators share this rule: hidden and deprecated
+// commands, additional help topic commands and the help command are skipped.
+func documented(cmd *cobra.Command) bool {
+	return cmd.IsAvailableCommand() && !cmd.IsAdditionalHelpTopicCommand()
+}
+
+// documentedFlags returns the flags of fs that are shown in the docs, in the
+// order they are visited. Hidden and deprecated flags are left out, as they
+// are in the usage output.
+func documentedFlags(fs *pflag.FlagSet) []*pflag.Flag {
+	var flags []*pflag.Flag
+	fs.VisitAll(func(flag *pflag.Flag) {
+		if flag.Hidden || len(flag.Deprecated) > 0 {
+			return
+		}
+		flags = append(flags, flag)
+	})
+	return flags
+}
+
+// Test to see if we have a reason to print See Also information in docs
+// Basically this is a test for a parent command or a subcommand which is
+// both not deprecated and not the autogenerated help command.
+func hasSeeAlso(cmd *cobra.Command) bool {
+	if cmd.HasParent() {
+		return true
+	}
+	for _, c := range cmd.Commands() {
+		if documented(c) {
+			return true
+		}
+	}
+	return false
+}
+
+type byName []*cobra.Command
+
+func (s byName) Len() int           { return len(s) }
+func (s byName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byName) Less(i, j int) bool { return s[i].Name() < s[j].Name() }
+
+// sortedChildren returns the sub-commands of cmd sorted by name, without
+// reordering the sub-commands of cmd itself.
+func sortedChildren(cmd *cobra.Command) []*cobra.Command {
+	children := append([]*cobra.Command(nil), cmd.Commands()...)
+	sort.Sort(byName(children))
+	return children
+}
+
+// commandGroup is a titled set of sub-commands, as listed under the group
+// headings of the default usage template.
+type commandGroup struct {
+	Title    string
+	Commands []*cobra.Command
+}
+
+// groupedChildren returns the documented sub-commands of cmd sorted by name
+// and split by the groups defined on cmd. Sub-commands without a group come
+// last under "Additional Commands". If cmd defines no groups, a single
+// untitled group is returned.
+func groupedChildren(cmd *cobra.Command) []commandGroup {
+	var listed []*cobra.Command
+	for _, child := range sortedChildren(cmd) {
+		if documented(child) {
+			listed = append(listed, child)
+		}
+	}
+	if len(listed) == 0 {
+		return nil
+	}
+	if len(cmd.Groups()) == 0 {
+		return []commandGroup{{Commands: listed}}
+	}
+
+	var groups []commandGroup
+	for _, group := range cmd.Groups() {
+		cg := commandGroup{Title: strings.TrimSuffix(strings.TrimSpace(group.Title), ":")}
+		for _, child := range listed {
+			if child.GroupID == group.ID {
+				cg.Commands = append(cg.Commands, child)
+			}
+		}
+		if len(cg.Commands) > 0 {
+			groups = append(groups, cg)
+		}
+	}
+	additional := commandGroup{Title: "Additional Commands"}
+	for _, child := range listed {
+		if child.GroupID == "" {
+			additional.Commands = append(additional.Commands, child)
+		}
+	}
+	if len(additional.Commands) > 0 {
+		groups = append(groups, additional)
+	}
+	return groups
+}
@@ -0,0 +1,252 @@
+package cobra
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The errors below are returned by the argument validators, Find,
+// ValidateRequiredFlags, ValidateFlagGroups and ParseFlags. They are returned
+// as pointers, so that they can be matched with errors.As:
+//
+//	var countErr *cobra.ArgCountError
+//	if errors.As(err, &countErr) {
+//		...
+//	}
+
+// UnknownCommandError is returned when an argument does not name a subcommand
+// of a command which takes no arguments of its own.
+type UnknownCommandError struct {
+	// Cmd is the command the argument was given to.
+	Cmd *Command
+	// Arg is the unknown argument.
+	Arg string
+	// Suggestions are the subcommands of Cmd the argument may have been meant for.
+	Suggestions []string
+}
+
+func (e *UnknownCommandError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "unknown command %q for %q", e.Arg, e.Cmd.CommandPath())
+	writeSuggestions(&sb, e.Suggestions)
+	return sb.String()
+}
+
+// ArgCountError is returned when a command is given a number of arguments
+// it does not accept. Max is -1 when there is no upper bound.
+type ArgCountError struct {
+	Min int
+	Max int
+	Got int
+}
+
+func (e *ArgCountError) Error() string {
+	switch {
+	case e.Min == e.Max:
+		return fmt.Sprintf("accepts %d arg(s), received %d", e.Min, e.Got)
+	case e.Max < 0:
+		return fmt.Sprintf("requires at least %d arg(s), only received %d", e.Min, e.Got)
+	case e.Min <= 0:
+		return fmt.Sprintf("accepts at most %d arg(s), received %d", e.Max, e.Got)
+	default:
+		return fmt.Sprintf("accepts between %d and %d arg(s), received %d", e.Min, e.Max, e.Got)
+	}
+}
+
+// InvalidArgError is returned when an argument is not one of the ValidArgs of a command.
+type InvalidArgError struct {
+	// Cmd is the command the argument was given to.
+	Cmd *Command
+	// Arg is the invalid argument.
+	Arg string
+	// Suggestions are the valid arguments the argument may have been meant for.
+	Suggestions []string
+}
+
+func (e *InvalidArgError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "invalid argument %q for %q", e.Arg, e.Cmd.CommandPath())
+	writeSuggestions(&sb, e.Suggestions)
+	return sb.String()
+}
+
+// RequiredFlagsError is returned when flags marked as required are not set.
+type RequiredFlagsError struct {
+	// Flags are the names of the missing flags.
+	Flags []string
+}
+
+func (e *RequiredFlagsError) Error() string {
+	return fmt.Sprintf(`required flag(s) "%s" not set`, strings.Join(e.Flags, `", "`))
+}
+
+// FlagGroupError is returned when the flags of a flag group are not set
+// as the group requires.
+type FlagGroupError struct {
+	// Kind is one of the FlagGroup kinds, such as FlagGroupRequiredTogether.
+	Kind string
+	// Flags are the names of the flags in the group. For FlagGroupRequires and
+	// FlagGroupRequiresValue, the first flag is the one requiring the others.
+	Flags []string
+	// Missing are the flags of a FlagGroupRequiredTogether or FlagGroupRequires
+	// group which are not set.
+	Missing []string
+	// Set are the flags of a FlagGroupMutuallyExclusive, FlagGroupExactlyOne or
+	// FlagGroupAtMost group which are set.
+	Set []string
+	// Max is the number of flags of a FlagGroupAtMost group which may be set.
+	Max int
+	// Value is the value required by a FlagGroupRequiresValue group.
+	Value string
+	// Arg is the positional argument a FlagGroupRequiredWithArg flag is required with.
+	Arg string
+}
+
+func (e *FlagGroupError) Error() string {
+	group := strings.Join(e.Flags, " ")
+	switch e.Kind {
+	case FlagGroupRequiredTogether:
+		return fmt.Sprintf("if any flags in the group [%v] are set they must all be set; missing %v", group, e.Missing)
+	case FlagGroupOneRequired:
+		return fmt.Sprintf("at least one of the flags in the group [%v] is required", group)
+	case FlagGroupMutuallyExclusive:
+		return fmt.Sprintf("if any flags in the group [%v] are set none of the others can be; %v were all set", group, e.Set)
+	case FlagGroupExactlyOne:
+		if len(e.Set) == 0 {
+			return fmt.Sprintf("exactly one of the flags in the group [%v] is required", group)
+		}
+		return fmt.Sprintf("exactly one of the flags in the group [%v] can be set; %v were all set", group, e.Set)
+	case FlagGroupAtMost:
+		return fmt.Sprintf("at most %d of the flags in the group [%v] can be set; %v were all set", e.Max, group, e.Set)
+	case FlagGroupRequires:
+		return fmt.Sprintf("if flag %v is set %v must be set too; missing %v", e.Flags[0], e.Flags[1:], e.Missing)
+	case FlagGroupRequiresValue:
+		return fmt.Sprintf("if flag %v is set flag %v must be %q", e.Flags[0], e.Flags[1], e.Value)
+	case FlagGroupRequiredWithArg:
+		return fmt.Sprintf("flag %v is required with the argument %q", group, e.Arg)
+	default:
+		return fmt.Sprintf("invalid use of the flags in the group [%v]", group)
+	}
+}
+
+// FlagParseError is returned when the flags of a command cannot be parsed,
+// such as for an unknown flag or an invalid value. Err is the error of pflag,
+// or the error of the environment variable or config file a value came from.
+type FlagParseError struct {
+	Err error
+}
+
+func (e *FlagParseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FlagParseError) Unwrap() error {
+	return e.Err
+}
+
+// ArgParseError is returned when a positional argument declared with Arg
+// cannot be parsed by its type.
+type ArgParseError struct {
+	// Cmd is the command the argument was given to.
+	Cmd *Command
+	// Name and Type are the name and the type name of the declared argument.
+	Name string
+	Type string
+	// Arg is the invalid argument.
+	Arg string
+	// Err is the error of the ArgType.
+	Err error
+}
+
+func (e *ArgParseError) Error() string {
+	return fmt.Sprintf("invalid %s argument %q for %q: %v", e.Type, e.Arg, e.Name, e.Err)
+}
+
+func (e *ArgParseError) Unwrap() error {
+	return e.Err
+}
+
+// ArgError is returned by the validators checking every argument, such as
+// EachArg or ArgsAreFiles, for the first argument they reject.
+type ArgError struct {
+	// Index is the position of the argument.
+	Index int
+	// Arg is the rejected argument.
+	Arg string
+	// Constraint describes the check of the validator, such as "each an existing file".
+	Constraint string
+	// Err is why the argument was rejected.
+	Err error
+}
+
+func (e *ArgError) Error() string {
+	return fmt.Sprintf("invalid argument %q: %v", e.Arg, e.Err)
+}
+
+func (e *ArgError) Unwrap() error {
+	return e.Err
+}
+
+// ArgsConstraintError is returned by the validators combining others, such
+// as AnyOf or Not, when the arguments do not meet their constraint.
+type ArgsConstraintError struct {
+	// Constraint is the ArgsConstraint of the command, if any.
+	Constraint string
+	// Errors are the errors of the combined validators, if any.
+	Errors []error
+}
+
+func (e *ArgsConstraintError) Error() string {
+	msg := "invalid arguments"
+	if e.Constraint != "" {
+		msg += ", expected " + e.Constraint
+	}
+	if len(e.Errors) > 0 {
+		errs := make([]string, 0, len(e.Errors))
+		for _, err := range e.Errors {
+			errs = append(errs, err.Error())
+		}
+		msg += ": " + strings.Join(errs, "; ")
+	}
+	return msg
+}
+
+func (e *ArgsConstraintError) Unwrap() []error {
+	return e.Errors
+}
+
+// PanicError is returned by ExecuteC for a panic of the executed command,
+// when RecoverPanics is set on the root command.
+type PanicError struct {
+	// CommandPath is the path of the command which panicked.
+	CommandPath string
+	// Args are the arguments of the command, with the values of the
+	// flags marked by MarkFlagSensitive redacted.
+	Args []string
+	// Value is the value the command panicked with.
+	Value interface{}
+	// Stack is the stack trace of the panic.
+	Stack []byte
+	// ReportPath is the crash report written to CrashReportDir, if any.
+	ReportPath string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("%q panicked: %v", e.CommandPath, e.Value)
+}
+
+// Unwrap returns the value of the panic if it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+func writeSuggestions(sb *strings.Builder, suggestions []string) {
+	if len(suggestions) == 0 {
+		return
+	}
+	sb.WriteString("\n\n Did you mean this?\n")
+	for _, s := range suggestions {
+		fmt.Fprintf(sb, "\t%#v\n", s)
+	}
+}
@@ -0,0 +1,174 @@
+package cobra
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestUnknownCommandErrorSuggestions(t *testing.T) {
+	rootCmd := &Command{Use: "root"}
+	rootCmd.AddCommand(&Command{Use: "server", Run: emptyRun})
+
+	_, err := executeCommand(rootCmd, "serer")
+	var unknownErr *UnknownCommandError
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("Expected an UnknownCommandError, got %T: %v", err, err)
+	}
+	if unknownErr.Cmd != rootCmd || unknownErr.Arg != "serer" || !reflect.DeepEqual(unknownErr.Suggestions, []string{"server"}) {
+		t.Errorf("Unexpected error fields: %+v", unknownErr)
+	}
+	expected := "unknown command \"serer\" for \"root\"\n\n Did you mean this?\n\t\"server\"\n"
+	if err.Error() != expected {
+		t.Errorf("Expected message %q, got %q", expected, err.Error())
+	}
+}
+
+func TestInvalidArgErrorSuggestions(t *testing.T) {
+	c := &Command{Use: "c", ValidArgs: []string{"one", "two"}, Args: OnlyValidArgs, Run: emptyRun}
+
+	_, err := executeCommand(c, "on")
+	var invalidErr *InvalidArgError
+	if !errors.As(err, &invalidErr) {
+		t.Fatalf("Expected an InvalidArgError, got %T: %v", err, err)
+	}
+	if invalidErr.Arg != "on" || !reflect.DeepEqual(invalidErr.Suggestions, []string{"one"}) {
+		t.Errorf("Unexpected error fields: %+v", invalidErr)
+	}
+	expected := "invalid argument \"on\" for \"c\"\n\n Did you mean this?\n\t\"one\"\n"
+	if err.Error() != expected {
+		t.Errorf("Expected message %q, got %q", expected, err.Error())
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(c *Command)
+		args  []string
+		check func(t *testing.T, err error)
+	}{
+		{
+			name:  "arg count",
+			setup: func(c *Command) { c.Args = RangeArgs(1, 2) },
+			args:  []string{"a", "b", "c"},
+			check: func(t *testing.T, err error) {
+				var countErr *ArgCountError
+				if !errors.As(err, &countErr) {
+					t.Fatalf("Expected an ArgCountError, got %T: %v", err, err)
+				}
+				if *countErr != (ArgCountError{Min: 1, Max: 2, Got: 3}) {
+					t.Errorf("Unexpected error fields: %+v", countErr)
+				}
+			},
+		},
+		{
+			name: "required flags",
+			setup: func(c *Command) {
+				c.Flags().String("a", "", "")
+				c.Flags().String("b", "", "")
+				_ = c.MarkFlagRequired("a")
+				_ = c.MarkFlagRequired("b")
+			},
+			args: []string{"--b", "x"},
+			check: func(t *testing.T, err error) {
+				var requiredErr *RequiredFlagsError
+				if !errors.As(err, &requiredErr) {
+					t.Fatalf("Expected a RequiredFlagsError, got %T: %v", err, err)
+				}
+				if !reflect.DeepEqual(requiredErr.Flags, []string{"a"}) {
+					t.Errorf("Expected flags [a], got %v", requiredErr.Flags)
+				}
+			},
+		},
+		{
+			name: "flag group",
+			setup: func(c *Command) {
+				c.Flags().Bool("json", false, "")
+				c.Flags().Bool("yaml", false, "")
+				c.MarkFlagsMutuallyExclusive("json", "yaml")
+			},
+			args: []string{"--json", "--yaml"},
+			check: func(t *testing.T, err error) {
+				var groupErr *FlagGroupError
+				if !errors.As(err, &groupErr) {
+					t.Fatalf("Expected a FlagGroupError, got %T: %v", err, err)
+				}
+				if groupErr.Kind != FlagGroupMutuallyExclusive || !reflect.DeepEqual(groupErr.Set, []string{"json", "yaml"}) {
+					t.Errorf("Unexpected error fields: %+v", groupErr)
+				}
+			},
+		},
+		{
+			name:  "flag parse",
+			setup: func(c *Command) { c.Flags().Int("port", 0, "") },
+			args:  []string{"--port", "eighty"},
+			check: func(t *testing.T, err error) {
+				var parseErr *FlagParseError
+				if !errors.As(err, &parseErr) {
+					t.Fatalf("Expected a FlagParseError, got %T: %v", err, err)
+				}
+				if parseErr.Err == nil || errors.Unwrap(parseErr) != parseErr.Err {
+					t.Errorf("Expected the error of pflag to be wrapped, got %+v", parseErr)
+				}
+			},
+		},
+		{
+			name:  "arg parse",
+			setup: func(c *Command) { c.Arg("count", Int) },
+			args:  []string{"three"},
+			check: func(t *testing.T, err error) {
+				var parseErr *ArgParseError
+				if !errors.As(err, &parseErr) {
+					t.Fatalf("Expected an ArgParseError, got %T: %v", err, err)
+				}
+				if parseErr.Name != "count" || parseErr.Type != "int" || parseErr.Arg != "three" || parseErr.Err == nil {
+					t.Errorf("Unexpected error fields: %+v", parseErr)
+				}
+			},
+		},
+		{
+			name:  "arg",
+			setup: func(c *Command) { c.Args = ArgsAreFiles },
+			args:  []string{filepath.Join(t.TempDir(), "missing")},
+			check: func(t *testing.T, err error) {
+				var argErr *ArgError
+				if !errors.As(err, &argErr) {
+					t.Fatalf("Expected an ArgError, got %T: %v", err, err)
+				}
+				if argErr.Index != 0 || argErr.Constraint != "each an existing file" || argErr.Err == nil {
+					t.Errorf("Unexpected error fields: %+v", argErr)
+				}
+			},
+		},
+		{
+			name:  "args constraint",
+			setup: func(c *Command) { c.Args = AnyOf(NoArgs, ExactArgs(2)) },
+			args:  []string{"a"},
+			check: func(t *testing.T, err error) {
+				var constraintErr *ArgsConstraintError
+				if !errors.As(err, &constraintErr) {
+					t.Fatalf("Expected an ArgsConstraintError, got %T: %v", err, err)
+				}
+				if len(constraintErr.Errors) != 2 {
+					t.Errorf("Expected the errors of both validators, got %v", constraintErr.Errors)
+				}
+				// the errors of the combined validators are matched too
+				var countErr *ArgCountError
+				if !errors.As(err, &countErr) {
+					t.Error("Expected to find the ArgCountError of ExactArgs")
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Command{Use: "c", Run: emptyRun}
+			tc.setup(c)
+			_, err := executeCommand(c, tc.args...)
+			tc.check(t, err)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package cobra
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// Exit codes of ExitCode.
+const (
+	ExitOK       = 0
+	ExitError    = 1
+	ExitUsage    = 2
+	ExitCanceled = 130
+)
+
+// ExitCoder is implemented by errors which set the exit code of the program.
+// An error returned from RunE or a hook may implement it to pick its own code.
+type ExitCoder interface {
+	error
+	ExitCode() int
+}
+
+// ExitCode returns the exit code of the program for err, an error returned by
+// executing c. The function set by SetExitCodeFunc on c or its nearest parent
+// is used if there is one. Otherwise it is:
+//   - ExitOK if err is nil;
+//   - the code of the first ExitCoder in the chain of err;
+//   - ExitCanceled if err is or wraps context.Canceled;
+//   - ExitError for any other error.
+//
+// The errors of the argument and flag validation, such as ArgCountError or
+// FlagParseError, are ExitCoders returning ExitUsage.
+func (c *Command) ExitCode(err error) int {
+	for p := c; p != nil; p = p.Parent() {
+		if p.exitCodeFunc != nil {
+			return p.exitCodeFunc(err)
+		}
+	}
+	return exitCode(err)
+}
+
+func exitCode(err error) int {
+	var coder ExitCoder
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.As(err, &coder):
+		return coder.ExitCode()
+	case errors.Is(err, context.Canceled):
+		return ExitCanceled
+	default:
+		return ExitError
+	}
+}
+
+// ExecuteAndExit executes root and exits the program with the ExitCode of
+// the error if there is one. The error is printed by ExecuteC with the
+// ErrPrefix of the command, unless SilenceErrors is set.
+func ExecuteAndExit(root *Command) {
+	cmd, err := root.ExecuteC()
+	if err == nil {
+		return
+	}
+	if cmd == nil {
+		cmd = root
+	}
+	os.Exit(cmd.ExitCode(err))
+}
+
+func (e *UnknownCommandError) ExitCode() int { return ExitUsage }
+func (e *ArgCountError) ExitCode() int       { return ExitUsage }
+func (e *InvalidArgError) ExitCode() int     { return ExitUsage }
+func (e *ArgParseError) ExitCode() int       { return ExitUsage }
+func (e *ArgError) ExitCode() int            { return ExitUsage }
+func (e *ArgsConstraintError) ExitCode() int { return ExitUsage }
+func (e *RequiredFlagsError) ExitCode() int  { return ExitUsage }
+func (e *FlagGroupError) ExitCode() int      { return ExitUsage }
+func (e *FlagParseError) ExitCode() int      { return ExitUsage }
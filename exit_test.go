@@ -0,0 +1,137 @@
+package cobra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+type exitError struct{ code int }
+
+func (e *exitError) Error() string { return fmt.Sprintf("exit %d", e.code) }
+func (e *exitError) ExitCode() int { return e.code }
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"nil", nil, ExitOK},
+		{"error", errors.New("failed"), ExitError},
+		{"unknown command", &UnknownCommandError{}, ExitUsage},
+		{"arg count", &ArgCountError{}, ExitUsage},
+		{"invalid arg", &InvalidArgError{}, ExitUsage},
+		{"arg parse", &ArgParseError{}, ExitUsage},
+		{"arg", &ArgError{}, ExitUsage},
+		{"args constraint", &ArgsConstraintError{}, ExitUsage},
+		{"required flags", &RequiredFlagsError{}, ExitUsage},
+		{"flag group", &FlagGroupError{}, ExitUsage},
+		{"flag parse", &FlagParseError{Err: errors.New("bad flag")}, ExitUsage},
+		{"wrapped", fmt.Errorf("running: %w", &ArgCountError{}), ExitUsage},
+		{"exit coder", &exitError{code: 3}, 3},
+		{"wrapped exit coder", fmt.Errorf("running: %w", &exitError{code: 4}), 4},
+		{"canceled", context.Canceled, ExitCanceled},
+		{"wrapped canceled", fmt.Errorf("waiting: %w", context.Canceled), ExitCanceled},
+		{"panic", &PanicError{Value: "boom"}, ExitError},
+	}
+
+	c := &Command{Use: "c"}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if code := c.ExitCode(tc.err); code != tc.code {
+				t.Errorf("Expected exit code %d, got %d", tc.code, code)
+			}
+		})
+	}
+}
+
+func TestSetExitCodeFunc(t *testing.T) {
+	rootCmd := &Command{Use: "root"}
+	childCmd := &Command{Use: "child"}
+	grandchildCmd := &Command{Use: "grandchild"}
+	childCmd.AddCommand(grandchildCmd)
+	rootCmd.AddCommand(childCmd)
+
+	rootCmd.SetExitCodeFunc(func(error) int { return 10 })
+	childCmd.SetExitCodeFunc(func(err error) int {
+		if err == nil {
+			return 0
+		}
+		return 20
+	})
+
+	err := errors.New("failed")
+	if code := rootCmd.ExitCode(err); code != 10 {
+		t.Errorf("Expected exit code 10 for root, got %d", code)
+	}
+	if code := grandchildCmd.ExitCode(err); code != 20 {
+		t.Errorf("Expected the exit code of the nearest parent, got %d", code)
+	}
+	if code := grandchildCmd.ExitCode(nil); code != 0 {
+		t.Errorf("Expected the function to get a nil error, got %d", code)
+	}
+}
+
+// exitHelperEnv names the case TestExecuteAndExit runs in a child process.
+const exitHelperEnv = "COBRA_TEST_EXIT_CASE"
+
+func TestExecuteAndExit(t *testing.T) {
+	tests := []struct {
+		name string
+		code int
+	}{
+		{"ok", ExitOK},
+		{"usage", ExitUsage},
+		{"exit coder", 3},
+		{"exit code func", 42},
+		{"check error", ExitUsage},
+		{"check value", ExitError},
+	}
+
+	if name := os.Getenv(exitHelperEnv); name != "" {
+		runExitCase(name)
+		return
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestExecuteAndExit$")
+			cmd.Env = append(os.Environ(), exitHelperEnv+"="+tc.name)
+			err := cmd.Run()
+
+			code := 0
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				code = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if code != tc.code {
+				t.Errorf("Expected exit code %d, got %d", tc.code, code)
+			}
+		})
+	}
+}
+
+func runExitCase(name string) {
+	c := &Command{Use: "c", Args: NoArgs, Run: emptyRun, SilenceErrors: true, SilenceUsage: true}
+	c.SetArgs(nil)
+	switch name {
+	case "usage":
+		c.SetArgs([]string{"--unknown"})
+	case "exit coder":
+		c.RunE = func(*Command, []string) error { return &exitError{code: 3} }
+	case "exit code func":
+		c.RunE = func(*Command, []string) error { return errors.New("failed") }
+		c.SetExitCodeFunc(func(error) int { return 42 })
+	case "check error":
+		CheckErr(&FlagParseError{Err: errors.New("bad flag")})
+	case "check value":
+		CheckErr("failed")
+	}
+	ExecuteAndExit(c)
+}
@@ -0,0 +1,68 @@
+package cobra
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenFishCompletion(t *testing.T) {
+	rootCmd := &Command{Use: "my-prog", Run: emptyRun}
+	rootCmd.AddCommand(&Command{Use: "child", Run: emptyRun})
+
+	for _, includeDesc := range []bool{true, false} {
+		compCmd := ShellCompRequestCmd
+		if !includeDesc {
+			compCmd = ShellCompNoDescRequestCmd
+		}
+
+		buf := new(bytes.Buffer)
+		if err := rootCmd.GenFishCompletion(buf, includeDesc); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		out := buf.String()
+
+		expected := []string{
+			"# fish completion for my-prog",
+			"function __my_prog_perform_completion\n",
+			"complete -c my-prog -e\n",
+			fmt.Sprintf(`set -l requestComp "%s=0 $args[1] %s $args[2..-1] $lastArg"`, activeHelpEnvVar("my-prog"), compCmd),
+			fmt.Sprintf("set -l shellCompDirectiveError %d\n", ShellCompDirectiveError),
+			fmt.Sprintf("set -l shellCompDirectiveNoSpace %d\n", ShellCompDirectiveNoSpace),
+			fmt.Sprintf("set -l shellCompDirectiveNoFileComp %d\n", ShellCompDirectiveNoFileComp),
+			fmt.Sprintf("set -l shellCompDirectiveFilterFileExt %d\n", ShellCompDirectiveFilterFileExt),
+			fmt.Sprintf("set -l shellCompDirectiveFilterDirs %d\n", ShellCompDirectiveFilterDirs),
+			fmt.Sprintf("set -l shellCompDirectiveKeepOrder %d\n", ShellCompDirectiveKeepOrder),
+		}
+		for _, s := range expected {
+			if !strings.Contains(out, s) {
+				t.Errorf("Expected %q in the %s script", s, compCmd)
+			}
+		}
+		if strings.Contains(out, "__my-prog_") {
+			t.Errorf("Expected the function names of the %s script to replace '-' in the program name", compCmd)
+		}
+		if strings.Contains(out, "%!") {
+			t.Errorf("Unexpected formatting error in the %s script", compCmd)
+		}
+	}
+}
+
+func TestGenFishCompletionFile(t *testing.T) {
+	rootCmd := &Command{Use: "prog", Run: emptyRun}
+	filename := filepath.Join(t.TempDir(), "prog.fish")
+
+	if err := rootCmd.GenFishCompletionFile(filename, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), "complete -c prog -e\n") {
+		t.Errorf("Expected a fish script for prog, got:\n%s", data)
+	}
+}
@@ -0,0 +1,153 @@
+package cobra
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	flag "github.com/spf13/pflag"
+)
+
+// BindFlagsToEnv makes the flags of c and all its descendants fall back to
+// environment variables when they are not set on the command line.
+// The variable for a flag is named PREFIX_SUBCMD_FLAG_NAME, where SUBCMD is
+// the path from c to the command defining the flag. If prefix is empty, the
+// name of c is used. A descendant may call BindFlagsToEnv to use another prefix.
+//
+// The values from the environment do not mark the flags as changed. They
+// satisfy MarkFlagRequired, but the flag groups and requirements only see
+// the flags given on the command line.
+func (c *Command) BindFlagsToEnv(prefix string) {
+	if prefix == "" {
+		prefix = c.Name()
+	}
+	c.envPrefix = prefix
+}
+
+// envBinder returns the nearest command, c included, on which
+// BindFlagsToEnv was called, or nil if there is none.
+func (c *Command) envBinder() *Command {
+	for p := c; p != nil; p = p.Parent() {
+		if p.envPrefix != "" {
+			return p
+		}
+	}
+	return nil
+}
+
+// flagOwner returns the command which defines f, either as one of its own
+// flags or as a persistent flag inherited by c.
+func (c *Command) flagOwner(f *flag.Flag) *Command {
+	for p := c; p != nil; p = p.Parent() {
+		if p.PersistentFlags().Lookup(f.Name) == f {
+			return p
+		}
+	}
+	return c
+}
+
+// FlagEnvVar returns the name of the environment variable bound to f,
+// or "" if the flags of c are not bound to the environment.
+func (c *Command) FlagEnvVar(f *flag.Flag) string {
+	binder := c.envBinder()
+	if binder == nil || f.Annotations[FlagSetByCoBraAnnotation] != nil {
+		return ""
+	}
+
+	// a flag inherited from above the binding command has no path
+	path, _ := c.flagOwner(f).relativePath(binder)
+	parts := append(append([]string{binder.envPrefix}, path...), f.Name)
+	return envVarName(strings.Join(parts, "_"))
+}
+
+// envVarName upper-cases s and replaces every character which is not
+// allowed in an environment variable name with an underscore.
+func envVarName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return unicode.ToUpper(r)
+		}
+		return '_'
+	}, s)
+}
+
+// setFlagsFromEnv sets every flag which was not given on the command line
+// from its environment variable, if that variable is set.
+func (c *Command) setFlagsFromEnv() error {
+	if c.DisableFlagParsing || c.envBinder() == nil {
+		return nil
+	}
+
+	var err error
+	c.Flags().VisitAll(func(f *flag.Flag) {
+		if err != nil || f.Changed {
+			return
+		}
+		name := c.FlagEnvVar(f)
+		if name == "" {
+			return
+		}
+		value, found := os.LookupEnv(name)
+		if !found {
+			return
+		}
+		if setErr := setFlagValue(f, value); setErr != nil {
+			err = &FlagParseError{Err: fmt.Errorf("invalid value %q for flag --%s from environment variable %s: %v", value, f.Name, name, setErr)}
+			return
+		}
+		setFlagSource(c.Flags(), f.Name, FlagSourceEnv)
+	})
+	return err
+}
+
+// FlagUsage returns the usage of f as the help shows it: with the
+// environment variable f is bound to appended, and followed by one line for
+// each requirement marked on it.
+func (c *Command) FlagUsage(f *flag.Flag) string {
+	usage := c.flagEnvUsage(f)
+	for _, requirement := range c.flagRequirementUsages(f) {
+		usage += "\n" + requirement
+	}
+	return usage
+}
+
+// flagEnvUsage returns the usage of f with the bound environment variable appended.
+func (c *Command) flagEnvUsage(f *flag.Flag) string {
+	if c.envBinder() != nil {
+		if name := c.FlagEnvVar(f); name != "" {
+			return fmt.Sprintf("%s [$%s]", f.Usage, name)
+		}
+	}
+	return f.Usage
+}
+
+// AnnotatedFlagUsages returns the usage of flags as the help shows it, with
+// the bound environment variable appended to the usage of every flag, and
+// the requirements marked on it listed under it. The flags need not be flags
+// of c, but they are described as seen from c.
+func (c *Command) AnnotatedFlagUsages(flags *flag.FlagSet) string {
+	annotated := flag.NewFlagSet(c.Name(), flag.ContinueOnError)
+	annotated.SortFlags = flags.SortFlags
+	flags.VisitAll(func(f *flag.Flag) {
+		fc := *f
+		fc.Usage = c.flagEnvUsage(f)
+		if len(c.flagRequirementUsages(f)) > 0 {
+			fc.Usage += "\n" + requirementMarker + f.Name + requirementMarker
+		}
+		annotated.AddFlag(&fc)
+	})
+	return c.insertFlagRequirements(flags, annotated.FlagUsages())
+}
+
+// LocalFlagUsages returns the usage of the local flags, including the
+// environment variables they are bound to and their requirements.
+func (c *Command) LocalFlagUsages() string {
+	return c.AnnotatedFlagUsages(c.LocalFlags())
+}
+
+// InheritedFlagUsages returns the usage of the inherited flags, including the
+// environment variables they are bound to and their requirements.
+func (c *Command) InheritedFlagUsages() string {
+	return c.AnnotatedFlagUsages(c.InheritedFlags())
+}
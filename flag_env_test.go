@@ -0,0 +1,162 @@
+package cobra
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEnvFlags(t *testing.T) {
+	rootCmd := &Command{Use: "root"}
+	rootCmd.PersistentFlags().String("name", "", "")
+	serveCmd := &Command{Use: "serve", Run: emptyRun}
+	serveCmd.Flags().Int("port", 0, "")
+	serveCmd.Flags().StringSlice("tags", []string{"x"}, "")
+	serveCmd.Flags().Bool("dry-run", false, "")
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.BindFlagsToEnv("app")
+
+	t.Setenv("APP_NAME", "it's")
+	t.Setenv("APP_SERVE_PORT", "8080")
+	t.Setenv("APP_SERVE_TAGS", "a,b")
+	t.Setenv("APP_SERVE_DRY_RUN", "true")
+
+	if _, err := executeCommand(rootCmd, "serve"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if name, _ := serveCmd.Flags().GetString("name"); name != "it's" {
+		t.Errorf("Expected name %q, got %q", "it's", name)
+	}
+	if port, _ := serveCmd.Flags().GetInt("port"); port != 8080 {
+		t.Errorf("Expected port 8080, got %d", port)
+	}
+	if tags, _ := serveCmd.Flags().GetStringSlice("tags"); !reflect.DeepEqual(tags, []string{"a", "b"}) {
+		t.Errorf("Expected tags [a b], got %v", tags)
+	}
+	if dryRun, _ := serveCmd.Flags().GetBool("dry-run"); !dryRun {
+		t.Error("Expected dry-run to be set")
+	}
+	for _, name := range []string{"name", "port", "tags", "dry-run"} {
+		if source := serveCmd.FlagSource(name); source != FlagSourceEnv {
+			t.Errorf("Expected source %q for %s, got %q", FlagSourceEnv, name, source)
+		}
+		if serveCmd.Flags().Changed(name) {
+			t.Errorf("Expected %s not to be changed", name)
+		}
+	}
+}
+
+func TestEnvFlagsInvalidValue(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.Flags().Int("port", 0, "")
+	rootCmd.BindFlagsToEnv("")
+
+	t.Setenv("ROOT_PORT", "eighty")
+
+	_, err := executeCommand(rootCmd)
+	if _, ok := err.(*FlagParseError); !ok {
+		t.Fatalf("Expected a FlagParseError, got %T: %v", err, err)
+	}
+	if !strings.Contains(err.Error(), "ROOT_PORT") {
+		t.Errorf("Expected the error to name ROOT_PORT, got %v", err)
+	}
+}
+
+func TestFlagSourcePrecedence(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		env    string
+		config string
+		want   string
+		source string
+	}{
+		{"default", nil, "", `{}`, "default", FlagSourceDefault},
+		{"config", nil, "", `{"name": "config"}`, "config", FlagSourceConfig},
+		{"env over config", nil, "env", `{"name": "config"}`, "env", FlagSourceEnv},
+		{"flag over env", []string{"--name", "flag"}, "env", `{"name": "config"}`, "flag", FlagSourceFlag},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rootCmd := &Command{Use: "root", Run: emptyRun}
+			rootCmd.Flags().String("config", "", "")
+			rootCmd.Flags().String("name", "default", "")
+			rootCmd.BindFlagsToEnv("app")
+			rootCmd.BindFlagsToConfig("config")
+
+			if tc.env != "" {
+				t.Setenv("APP_NAME", tc.env)
+			}
+			path := writeConfigFile(t, "config.json", tc.config)
+			if _, err := executeCommand(rootCmd, append([]string{"--config", path}, tc.args...)...); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if name, _ := rootCmd.Flags().GetString("name"); name != tc.want {
+				t.Errorf("Expected name %q, got %q", tc.want, name)
+			}
+			if source := rootCmd.FlagSource("name"); source != tc.source {
+				t.Errorf("Expected source %q, got %q", tc.source, source)
+			}
+		})
+	}
+}
+
+func TestEnvFlagsSatisfyRequiredFlags(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.Flags().String("token", "", "")
+	if err := rootCmd.MarkFlagRequired("token"); err != nil {
+		t.Fatal(err)
+	}
+	rootCmd.BindFlagsToEnv("app")
+
+	if _, err := executeCommand(rootCmd); err == nil {
+		t.Fatal("Expected an error without APP_TOKEN")
+	}
+
+	t.Setenv("APP_TOKEN", "secret")
+	if _, err := executeCommand(rootCmd); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestEnvFlagsIgnoredByFlagGroups(t *testing.T) {
+	rootCmd := &Command{Use: "root", Run: emptyRun}
+	rootCmd.Flags().Bool("json", false, "")
+	rootCmd.Flags().Bool("yaml", false, "")
+	rootCmd.MarkFlagsMutuallyExclusive("json", "yaml")
+	rootCmd.BindFlagsToEnv("app")
+
+	t.Setenv("APP_JSON", "true")
+	if _, err := executeCommand(rootCmd, "--yaml"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if _, err := executeCommand(rootCmd, "--json", "--yaml"); err == nil {
+		t.Error("Expected an error for --json with --yaml")
+	}
+}
+
+func TestEnvFlagUsage(t *testing.T) {
+	rootCmd := &Command{Use: "root"}
+	rootCmd.PersistentFlags().String("name", "", "the name")
+	serveCmd := &Command{Use: "serve", Run: emptyRun}
+	serveCmd.Flags().Int("port", 0, "the port")
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.BindFlagsToEnv("app")
+
+	output, err := executeCommand(rootCmd, "serve", "--help")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, want := range []string{"the port [$APP_SERVE_PORT]", "the name [$APP_NAME]"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in the help, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "$APP_SERVE_HELP") {
+		t.Errorf("Expected no variable for the help flag, got:\n%s", output)
+	}
+}
@@ -1,16 +1,35 @@
 package cobra
 
 import (
-	"flag"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
+
+	flag "github.com/spf13/pflag"
 )
 
 const (
 	requiredAsGroupAnnotation   = "cobra_annotation_required_if_others_set"
 	oneRequiredAnnotation       = "cobra_annotation_one_required"
 	mutuallyExclusiveAnnotation = "cobra_annotation_mutually_exclusive"
+	exactlyOneAnnotation        = "cobra_annotation_exactly_one"
+	atMostAnnotation            = "cobra_annotation_at_most"
+	requiresAnnotation          = "cobra_annotation_requires"
+	requiresValueAnnotation     = "cobra_annotation_requires_value"
+	requiredWithArgAnnotation   = "cobra_annotation_required_with_arg"
+)
+
+// Kinds of flag groups, as found in FlagGroupError and FlagGroupSchema.
+const (
+	FlagGroupRequiredTogether  = "required_together"
+	FlagGroupOneRequired       = "one_required"
+	FlagGroupMutuallyExclusive = "mutually_exclusive"
+	FlagGroupExactlyOne        = "exactly_one"
+	FlagGroupAtMost            = "at_most"
+	FlagGroupRequires          = "requires"
+	FlagGroupRequiresValue     = "requires_value"
+	FlagGroupRequiredWithArg   = "required_with_arg"
 )
 
 func (c *Command) MarkFlagsRequiredTogether(flagNames ...string) {
@@ -53,7 +72,131 @@ func (c *Command) MarkFlagsMutuallyExclusive(flagNames ...string) {
 	}
 }
 
+// MarkFlagsExactlyOne marks the flags so that exactly one of them must be set.
+func (c *Command) MarkFlagsExactlyOne(flagNames ...string) {
+	c.mergePersistentFlags()
+	for _, v := range flagNames {
+		c.annotateFlag(v, exactlyOneAnnotation, strings.Join(flagNames, " "), "being in an exactly-one flag group")
+	}
+}
+
+// MarkFlagsAtMost marks the flags so that at most n of them may be set.
+func (c *Command) MarkFlagsAtMost(n int, flagNames ...string) {
+	c.mergePersistentFlags()
+	for _, v := range flagNames {
+		c.annotateFlag(v, atMostAnnotation, fmt.Sprintf("%d %s", n, strings.Join(flagNames, " ")), "being in an at-most flag group")
+	}
+}
+
+// MarkFlagRequires marks flagName as requiring the required flags: if it is
+// set, they must be set too.
+func (c *Command) MarkFlagRequires(flagName string, required ...string) {
+	c.mergePersistentFlags()
+	for _, v := range required {
+		c.lookupFlagToMark(v, "being required by flag "+flagName)
+		c.annotateFlag(flagName, requiresAnnotation, v, "requiring other flags")
+	}
+}
+
+// MarkFlagRequiresValue marks flagName as requiring the value of the flag
+// required to be value when it is set. The default value of required counts,
+// so it does not have to be set if its default is value.
+func (c *Command) MarkFlagRequiresValue(flagName, required, value string) {
+	c.mergePersistentFlags()
+	c.lookupFlagToMark(required, "being required by flag "+flagName)
+	c.annotateFlag(flagName, requiresValueAnnotation, required+"="+value, "requiring the value of another flag")
+}
+
+// MarkFlagRequiredWithArgs marks flagName as required when any of args is
+// among the positional arguments of the command. The arguments given as
+// aliases of ArgAliasTargets are compared by the values they stand for.
+func (c *Command) MarkFlagRequiredWithArgs(flagName string, args ...string) {
+	c.mergePersistentFlags()
+	for _, arg := range args {
+		c.annotateFlag(flagName, requiredWithArgAnnotation, arg, "required with arguments")
+	}
+}
+
+// MarkPersistentFlagsRequiredTogether is like MarkFlagsRequiredTogether for
+// persistent flags, but the group is also enforced on every descendant of c.
+func (c *Command) MarkPersistentFlagsRequiredTogether(flagNames ...string) {
+	c.markPersistentFlagGroup(requiredAsGroupAnnotation, strings.Join(flagNames, " "), flagNames)
+}
+
+// MarkPersistentFlagsOneRequired is like MarkFlagsOneRequired for
+// persistent flags, but the group is also enforced on every descendant of c.
+func (c *Command) MarkPersistentFlagsOneRequired(flagNames ...string) {
+	c.markPersistentFlagGroup(oneRequiredAnnotation, strings.Join(flagNames, " "), flagNames)
+}
+
+// MarkPersistentFlagsMutuallyExclusive is like MarkFlagsMutuallyExclusive for
+// persistent flags, but the group is also enforced on every descendant of c.
+func (c *Command) MarkPersistentFlagsMutuallyExclusive(flagNames ...string) {
+	c.markPersistentFlagGroup(mutuallyExclusiveAnnotation, strings.Join(flagNames, " "), flagNames)
+}
+
+// MarkPersistentFlagsExactlyOne is like MarkFlagsExactlyOne for
+// persistent flags, but the group is also enforced on every descendant of c.
+func (c *Command) MarkPersistentFlagsExactlyOne(flagNames ...string) {
+	c.markPersistentFlagGroup(exactlyOneAnnotation, strings.Join(flagNames, " "), flagNames)
+}
+
+// MarkPersistentFlagsAtMost is like MarkFlagsAtMost for
+// persistent flags, but the group is also enforced on every descendant of c.
+func (c *Command) MarkPersistentFlagsAtMost(n int, flagNames ...string) {
+	c.markPersistentFlagGroup(atMostAnnotation, fmt.Sprintf("%d %s", n, strings.Join(flagNames, " ")), flagNames)
+}
+
+// markPersistentFlagGroup records group on c rather than annotating the flags,
+// so that it is evaluated against the merged flags of whichever descendant runs.
+// The flags must be persistent flags of c or of its parents.
+func (c *Command) markPersistentFlagGroup(annotation, group string, flagNames []string) {
+	c.mergePersistentFlags()
+	for _, v := range flagNames {
+		if c.PersistentFlags().Lookup(v) == nil && c.parentsPflags.Lookup(v) == nil {
+			panic(fmt.Sprintf("Failed to find persistent flag %q and mark it as being in a persistent flag group", v))
+		}
+	}
+	if c.persistentFlagGroups == nil {
+		c.persistentFlagGroups = make(map[string][]string)
+	}
+	c.persistentFlagGroups[annotation] = append(c.persistentFlagGroups[annotation], group)
+}
+
+// inheritedFlagGroups returns the groups of the kind of annotation marked by
+// the MarkPersistentFlags functions on c and its parents.
+func (c *Command) inheritedFlagGroups(annotation string) []string {
+	var groups []string
+	for p := c; p != nil; p = p.Parent() {
+		groups = append(groups, p.persistentFlagGroups[annotation]...)
+	}
+	return groups
+}
+
+func (c *Command) lookupFlagToMark(flagName, purpose string) *flag.Flag {
+	f := c.Flags().Lookup(flagName)
+	if f == nil {
+		panic(fmt.Sprintf("Failed to find flag %q and mark it as %s", flagName, purpose))
+	}
+	return f
+}
+
+// annotateFlag appends value to the annotation of the flag flagName.
+// Each value is a separate entry, so a flag may be in several groups.
+func (c *Command) annotateFlag(flagName, annotation, value, purpose string) {
+	f := c.lookupFlagToMark(flagName, purpose)
+	if err := c.Flags().SetAnnotation(flagName, annotation, append(f.Annotations[annotation], value)); err != nil {
+		panic(err)
+	}
+}
+
 func (c *Command) ValidateFlagGroups() error {
+	return c.validateFlagGroups(c.resolveArgAliases(c.Flags().Args()))
+}
+
+// validateFlagGroups validates the flag groups and requirements of c, with
+// args as the arguments of the command, which may have been prompted for.
+func (c *Command) validateFlagGroups(args []string) error {
 	if c.DisableFlagParsing {
 		return nil
 	}
@@ -63,11 +206,14 @@ func (c *Command) ValidateFlagGroups() error {
 	groupStatus := map[string]map[string]bool{}
 	oneRequiredGroupStatus := map[string]map[string]bool{}
 	mutuallyExclusiveGroupStatus := map[string]map[string]bool{}
-	flag.VisitAll(func(pflag *flag.Flag) {
+	flags.VisitAll(func(pflag *flag.Flag) {
 		processFlagGroupAnnotation(flags, pflag, requiredAsGroupAnnotation, groupStatus)
 		processFlagGroupAnnotation(flags, pflag, oneRequiredAnnotation, oneRequiredGroupStatus)
 		processFlagGroupAnnotation(flags, pflag, mutuallyExclusiveAnnotation, mutuallyExclusiveGroupStatus)
 	})
+	c.processInheritedFlagGroups(flags, requiredAsGroupAnnotation, groupStatus)
+	c.processInheritedFlagGroups(flags, oneRequiredAnnotation, oneRequiredGroupStatus)
+	c.processInheritedFlagGroups(flags, mutuallyExclusiveAnnotation, mutuallyExclusiveGroupStatus)
 
 	if err := validateRequireFlagGroups(groupStatus); err != nil {
 		return err
@@ -78,17 +224,216 @@ func (c *Command) ValidateFlagGroups() error {
 	if err := validateExclusiveFlagGroups(mutuallyExclusiveGroupStatus); err != nil {
 		return err
 	}
+	return c.validateFlagRequirements(flags, args)
+}
+
+// processInheritedFlagGroups adds the status of the inherited flag groups of
+// the kind of annotation to groupStatus, like processFlagGroupAnnotation does
+// for the groups annotating a flag.
+func (c *Command) processInheritedFlagGroups(flags *flag.FlagSet, annotation string, groupStatus map[string]map[string]bool) {
+	for _, group := range c.inheritedFlagGroups(annotation) {
+		flagnames := strings.Split(group, " ")
+		if !hasAllFlags(flags, flagnames...) {
+			continue
+		}
+
+		groupStatus[group] = make(map[string]bool, len(flagnames))
+		for _, name := range flagnames {
+			groupStatus[group][name] = flags.Lookup(name).Changed
+		}
+	}
+}
+
+// validateFlagRequirements checks the flags marked by MarkFlagsExactlyOne,
+// MarkFlagsAtMost, MarkFlagRequires, MarkFlagRequiresValue and
+// MarkFlagRequiredWithArgs, and the inherited exactly-one and at-most
+// groups, args being the positional arguments.
+func (c *Command) validateFlagRequirements(flags *flag.FlagSet, args []string) error {
+	var err error
+	flags.VisitAll(func(pflag *flag.Flag) {
+		if err == nil {
+			err = validateFlagRequirement(flags, pflag, args)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, group := range c.inheritedFlagGroups(exactlyOneAnnotation) {
+		if err := validateExactlyOneGroup(flags, group); err != nil {
+			return err
+		}
+	}
+	for _, group := range c.inheritedFlagGroups(atMostAnnotation) {
+		if err := validateAtMostGroup(flags, group); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func hasAllFlags(fs *flag.FlagSet, flagnames ...string) bool {
-	for _, fname := range flagnames {
-		f := fs.Lookup(fname)
-		if f == nil {
-			return false
+func validateExactlyOneGroup(flags *flag.FlagSet, group string) error {
+	flagnames := strings.Split(group, " ")
+	if !hasAllFlags(flags, flagnames...) {
+		return nil
+	}
+	if set := changedFlags(flags, flagnames); len(set) != 1 {
+		return &FlagGroupError{Kind: FlagGroupExactlyOne, Flags: flagnames, Set: set}
+	}
+	return nil
+}
+
+func validateAtMostGroup(flags *flag.FlagSet, group string) error {
+	n, flagnames := splitAtMostGroup(group)
+	if !hasAllFlags(flags, flagnames...) {
+		return nil
+	}
+	if set := changedFlags(flags, flagnames); len(set) > n {
+		return &FlagGroupError{Kind: FlagGroupAtMost, Flags: flagnames, Max: n, Set: set}
+	}
+	return nil
+}
+
+func validateFlagRequirement(flags *flag.FlagSet, pflag *flag.Flag, args []string) error {
+	for _, group := range pflag.Annotations[exactlyOneAnnotation] {
+		if err := validateExactlyOneGroup(flags, group); err != nil {
+			return err
 		}
 	}
-	return true
+	for _, group := range pflag.Annotations[atMostAnnotation] {
+		if err := validateAtMostGroup(flags, group); err != nil {
+			return err
+		}
+	}
+
+	if pflag.Changed {
+		required := pflag.Annotations[requiresAnnotation]
+		var missing []string
+		for _, name := range required {
+			if f := flags.Lookup(name); f != nil && !f.Changed {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			return &FlagGroupError{Kind: FlagGroupRequires, Flags: append([]string{pflag.Name}, required...), Missing: missing}
+		}
+
+		for _, requirement := range pflag.Annotations[requiresValueAnnotation] {
+			name, value := splitRequiredValue(requirement)
+			if f := flags.Lookup(name); f != nil && f.Value.String() != value {
+				return &FlagGroupError{Kind: FlagGroupRequiresValue, Flags: []string{pflag.Name, name}, Value: value}
+			}
+		}
+	} else {
+		for _, arg := range pflag.Annotations[requiredWithArgAnnotation] {
+			if stringInSlice(arg, args) {
+				return &FlagGroupError{Kind: FlagGroupRequiredWithArg, Flags: []string{pflag.Name}, Arg: arg}
+			}
+		}
+	}
+	return nil
+}
+
+// changedFlags returns the flags of flagnames which are set, sorted.
+func changedFlags(flags *flag.FlagSet, flagnames []string) []string {
+	var set []string
+	for _, name := range flagnames {
+		if f := flags.Lookup(name); f != nil && f.Changed {
+			set = append(set, name)
+		}
+	}
+	sort.Strings(set)
+	return set
+}
+
+// splitAtMostGroup splits an annotation of MarkFlagsAtMost into the number
+// of flags which may be set and the names of the flags.
+func splitAtMostGroup(group string) (int, []string) {
+	fields := strings.Split(group, " ")
+	n, _ := strconv.Atoi(fields[0])
+	return n, fields[1:]
+}
+
+// splitRequiredValue splits an annotation of MarkFlagRequiresValue into the
+// name of the required flag and its value.
+func splitRequiredValue(requirement string) (string, string) {
+	name, value, _ := strings.Cut(requirement, "=")
+	return name, value
+}
+
+// flagRequirementUsages returns the lines describing the requirements
+// marked on f, and the inherited exactly-one and at-most groups it is in,
+// shown under its usage.
+func (c *Command) flagRequirementUsages(f *flag.Flag) []string {
+	var lines []string
+	exactlyOneGroups := append([]string{}, f.Annotations[exactlyOneAnnotation]...)
+	for _, group := range c.inheritedFlagGroups(exactlyOneAnnotation) {
+		if stringInSlice(f.Name, strings.Split(group, " ")) && !stringInSlice(group, exactlyOneGroups) {
+			exactlyOneGroups = append(exactlyOneGroups, group)
+		}
+	}
+	for _, group := range exactlyOneGroups {
+		lines = append(lines, "exactly one of "+dashedFlags(strings.Split(group, " ")))
+	}
+
+	atMostGroups := append([]string{}, f.Annotations[atMostAnnotation]...)
+	for _, group := range c.inheritedFlagGroups(atMostAnnotation) {
+		if _, flagnames := splitAtMostGroup(group); stringInSlice(f.Name, flagnames) && !stringInSlice(group, atMostGroups) {
+			atMostGroups = append(atMostGroups, group)
+		}
+	}
+	for _, group := range atMostGroups {
+		n, flagnames := splitAtMostGroup(group)
+		lines = append(lines, fmt.Sprintf("at most %d of %s", n, dashedFlags(flagnames)))
+	}
+	if required := f.Annotations[requiresAnnotation]; len(required) > 0 {
+		lines = append(lines, "requires "+dashedFlags(required))
+	}
+	for _, requirement := range f.Annotations[requiresValueAnnotation] {
+		name, value := splitRequiredValue(requirement)
+		lines = append(lines, fmt.Sprintf("requires --%s=%s", name, value))
+	}
+	for _, arg := range f.Annotations[requiredWithArgAnnotation] {
+		lines = append(lines, fmt.Sprintf("required with the argument %q", arg))
+	}
+	return lines
+}
+
+// requirementMarker encloses the name of a flag on the line of its usage
+// where its requirements are inserted.
+const requirementMarker = "\x01"
+
+// insertFlagRequirements replaces the marker lines in usages with the
+// requirements of their flags. pflag appends the default value of a flag to
+// the last line of its usage, the marker line, so it is moved to the line before.
+func (c *Command) insertFlagRequirements(flags *flag.FlagSet, usages string) string {
+	if !strings.Contains(usages, requirementMarker) {
+		return usages
+	}
+	lines := strings.Split(usages, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		start := strings.Index(line, requirementMarker)
+		if start < 0 || len(out) == 0 {
+			out = append(out, line)
+			continue
+		}
+		end := start + 1 + strings.Index(line[start+1:], requirementMarker)
+		indent, name, rest := line[:start], line[start+1:end], line[end+1:]
+		out[len(out)-1] += rest
+		for _, requirement := range c.flagRequirementUsages(flags.Lookup(name)) {
+			out = append(out, indent+requirement)
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+func dashedFlags(flagnames []string) string {
+	dashed := make([]string, len(flagnames))
+	for i, name := range flagnames {
+		dashed[i] = "--" + name
+	}
+	return strings.Join(dashed, ", ")
 }
 
 func hasAllFlags(fs *flag.FlagSet, flagnames ...string) bool {
@@ -139,7 +484,7 @@ func validateRequireFlagGroups(data map[string]map[string]bool) error {
 		}
 
 		sort.Strings(unset)
-		return fmt.Errorf("if any flags in the group [%v] are set they must all be set; missing %v", flagList, unset)
+		return &FlagGroupError{Kind: FlagGroupRequiredTogether, Flags: strings.Split(flagList, " "), Missing: unset}
 	}
 
 	return nil
@@ -159,8 +504,7 @@ func validateOneRequiredFlagGroups(data map[string]map[string]bool) error {
 			continue
 		}
 
-		sort.Strings(set)
-		return fmt.Errorf("at least one of the flags in the group [%v] is required", flagList)
+		return &FlagGroupError{Kind: FlagGroupOneRequired, Flags: strings.Split(flagList, " ")}
 	}
 	return nil
 }
@@ -181,7 +525,7 @@ func validateExclusiveFlagGroups(data map[string]map[string]bool) error {
 
 		// Sort values, so they can be tested/scripted against consistently.
 		sort.Strings(set)
-		return fmt.Errorf("if any flags in the group [%v] are set none of the others can be; %v were all set", flagList, set)
+		return &FlagGroupError{Kind: FlagGroupMutuallyExclusive, Flags: strings.Split(flagList, " "), Set: set}
 	}
 	return nil
 }
@@ -197,20 +541,27 @@ func sortedKeys(m map[string]map[string]bool) []string {
 	return keys
 }
 
-func (c *Command) enforceFlagGroupsForCompletion() {
+// enforceFlagGroupsForCompletion changes the flags of c so that the shell
+// completion follows the flag groups. It returns a function restoring the
+// flags, since the command tree may still be executed, as by RunShell.
+func (c *Command) enforceFlagGroupsForCompletion() (restore func()) {
 	if c.DisableFlagParsing {
-		return
+		return func() {}
 	}
 
 	flags := c.Flags()
+	restore = saveFlagsForCompletion(flags)
 	groupStatus := map[string]map[string]bool{}
 	oneRequiredGroupStatus := map[string]map[string]bool{}
 	mutuallyExclusiveGroupStatus := map[string]map[string]bool{}
 	c.Flags().VisitAll(func(pflag *flag.Flag) {
-		processFlagForGroupAnnotation(flags, pflag, requiredAsGroupAnnotation, groupStatus)
-		processFlagForGroupAnnotation(flags, pflag, oneRequiredAnnotation, oneRequiredGroupStatus)
-		processFlagForGroupAnnotation(flags, pflag, mutuallyExclusiveAnnotation, mutuallyExclusiveGroupStatus)
+		processFlagGroupAnnotation(flags, pflag, requiredAsGroupAnnotation, groupStatus)
+		processFlagGroupAnnotation(flags, pflag, oneRequiredAnnotation, oneRequiredGroupStatus)
+		processFlagGroupAnnotation(flags, pflag, mutuallyExclusiveAnnotation, mutuallyExclusiveGroupStatus)
 	})
+	c.processInheritedFlagGroups(flags, requiredAsGroupAnnotation, groupStatus)
+	c.processInheritedFlagGroups(flags, oneRequiredAnnotation, oneRequiredGroupStatus)
+	c.processInheritedFlagGroups(flags, mutuallyExclusiveAnnotation, mutuallyExclusiveGroupStatus)
 
 	// If a flag that is part of a group is present, we make all the other flags
 	// of that group required so that the shell completion suggests them automatically
@@ -262,4 +613,104 @@ func (c *Command) enforceFlagGroupsForCompletion() {
 			}
 		}
 	}
+
+	c.enforceFlagRequirementsForCompletion(flags, c.resolveArgAliases(flags.Args()))
+	return restore
+}
+
+// saveFlagsForCompletion returns a function restoring what the completion
+// changes on flags: whether they are required and hidden.
+func saveFlagsForCompletion(flags *flag.FlagSet) func() {
+	type savedFlag struct {
+		flag     *flag.Flag
+		hidden   bool
+		required []string
+		found    bool
+	}
+	var saved []savedFlag
+	flags.VisitAll(func(f *flag.Flag) {
+		required, found := f.Annotations[BashCompOneRequiredFlag]
+		saved = append(saved, savedFlag{flag: f, hidden: f.Hidden, required: required, found: found})
+	})
+
+	return func() {
+		for _, s := range saved {
+			s.flag.Hidden = s.hidden
+			if s.found {
+				s.flag.Annotations[BashCompOneRequiredFlag] = s.required
+			} else {
+				delete(s.flag.Annotations, BashCompOneRequiredFlag)
+			}
+		}
+	}
+}
+
+// enforceFlagRequirementsForCompletion does for the requirements checked by
+// validateFlagRequirements what enforceFlagGroupsForCompletion does for the
+// flag groups: the flags which must be set are made required, and the flags
+// which cannot be set are hidden.
+func (c *Command) enforceFlagRequirementsForCompletion(flags *flag.FlagSet, args []string) {
+	hideUnset := func(flagnames []string) {
+		for _, fName := range flagnames {
+			if f := flags.Lookup(fName); f != nil && !f.Changed {
+				f.Hidden = true
+			}
+		}
+	}
+
+	enforceExactlyOne := func(group string) {
+		flagnames := strings.Split(group, " ")
+		if !hasAllFlags(flags, flagnames...) {
+			return
+		}
+		if len(changedFlags(flags, flagnames)) == 0 {
+			for _, fName := range flagnames {
+				_ = c.MarkFlagRequired(fName)
+			}
+		} else {
+			hideUnset(flagnames)
+		}
+	}
+	enforceAtMost := func(group string) {
+		n, flagnames := splitAtMostGroup(group)
+		if hasAllFlags(flags, flagnames...) && len(changedFlags(flags, flagnames)) >= n {
+			hideUnset(flagnames)
+		}
+	}
+
+	for _, group := range c.inheritedFlagGroups(exactlyOneAnnotation) {
+		enforceExactlyOne(group)
+	}
+	for _, group := range c.inheritedFlagGroups(atMostAnnotation) {
+		enforceAtMost(group)
+	}
+
+	flags.VisitAll(func(pflag *flag.Flag) {
+		for _, group := range pflag.Annotations[exactlyOneAnnotation] {
+			enforceExactlyOne(group)
+		}
+		for _, group := range pflag.Annotations[atMostAnnotation] {
+			enforceAtMost(group)
+		}
+
+		if pflag.Changed {
+			for _, name := range pflag.Annotations[requiresAnnotation] {
+				if f := flags.Lookup(name); f != nil && !f.Changed {
+					_ = c.MarkFlagRequired(name)
+				}
+			}
+			for _, requirement := range pflag.Annotations[requiresValueAnnotation] {
+				name, value := splitRequiredValue(requirement)
+				if f := flags.Lookup(name); f != nil && !f.Changed && f.Value.String() != value {
+					_ = c.MarkFlagRequired(name)
+				}
+			}
+		} else {
+			for _, arg := range pflag.Annotations[requiredWithArgAnnotation] {
+				if stringInSlice(arg, args) {
+					_ = c.MarkFlagRequired(pflag.Name)
+				}
+			}
+		}
+	})
 }
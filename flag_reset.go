@@ -0,0 +1,127 @@
+package cobra
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+// resetFlagValues restores every flag of c and its descendants to its default
+// value, and clears the name they were called as.
+func (c *Command) resetFlagValues() error {
+	var err error
+	reset := func(f *flag.Flag) {
+		if !flagHasValue(f) {
+			return
+		}
+		if resetErr := c.resetFlagValue(f); resetErr != nil && err == nil {
+			err = resetErr
+		}
+		f.Changed = false
+		delete(f.Annotations, FlagValueSourceAnnotation)
+	}
+	c.Flags().VisitAll(reset)
+	c.PersistentFlags().VisitAll(reset)
+	c.commandCalledAs.name = ""
+	c.commandCalledAs.called = false
+
+	for _, sub := range c.commands {
+		if subErr := sub.resetFlagValues(); subErr != nil && err == nil {
+			err = subErr
+		}
+	}
+	return err
+}
+
+// resetFlagValue sets f back to its DefValue. A value which merges what it
+// is set to into its current value, as the map values of pflag do, cannot
+// be set back and is an error.
+func (c *Command) resetFlagValue(f *flag.Flag) error {
+	if sv, ok := f.Value.(flag.SliceValue); ok {
+		if err := sv.Replace(sliceDefault(f.DefValue)); err != nil {
+			return fmt.Errorf("cannot reset flag --%s to %q: %v", f.Name, f.DefValue, err)
+		}
+		if c.resetSlices == nil {
+			c.resetSlices = map[*flag.Flag]bool{}
+		}
+		c.resetSlices[f] = true
+		return nil
+	}
+
+	if err := f.Value.Set(f.DefValue); err != nil {
+		return fmt.Errorf("cannot reset flag --%s to %q: %v", f.Name, f.DefValue, err)
+	}
+	if value := f.Value.String(); value != f.DefValue {
+		return fmt.Errorf("cannot reset flag --%s to %q: its value is %q", f.Name, f.DefValue, value)
+	}
+	return nil
+}
+
+// sliceDefault parses the default value of a slice flag, such as [a,b].
+func sliceDefault(def string) []string {
+	def = strings.TrimSuffix(strings.TrimPrefix(def, "["), "]")
+	if def == "" {
+		return []string{}
+	}
+	values, err := csv.NewReader(strings.NewReader(def)).Read()
+	if err != nil {
+		return strings.Split(def, ",")
+	}
+	return values
+}
+
+// isResetSlice reports whether f is a slice flag ResetState set back to its
+// default on c or one of its parents.
+func (c *Command) isResetSlice(f *flag.Flag) bool {
+	for p := c; p != nil; p = p.Parent() {
+		if p.resetSlices[f] {
+			return true
+		}
+	}
+	return false
+}
+
+// clearResetSlices empties the slice flags of c which ResetState set back to
+// their default: once set, a slice value appends to its value on every Set
+// rather than replacing it, so that parsing would add to the default. It
+// returns a function setting the flags still not changed back to the default.
+func (c *Command) clearResetSlices() func() {
+	cleared := map[*flag.Flag][]string{}
+	c.Flags().VisitAll(func(f *flag.Flag) {
+		sv, ok := f.Value.(flag.SliceValue)
+		if !ok || f.Changed || !c.isResetSlice(f) {
+			return
+		}
+		def := sv.GetSlice()
+		if err := sv.Replace([]string{}); err == nil {
+			cleared[f] = def
+		}
+	})
+	return func() {
+		for f, def := range cleared {
+			if !f.Changed {
+				_ = f.Value.(flag.SliceValue).Replace(def)
+			}
+		}
+	}
+}
+
+// setFlagValue sets f to value without marking it as changed. The value of
+// a slice flag is replaced, rather than appended to once it was set.
+func setFlagValue(f *flag.Flag, value string) error {
+	sv, ok := f.Value.(flag.SliceValue)
+	if !ok {
+		return f.Value.Set(value)
+	}
+	def := sv.GetSlice()
+	if err := sv.Replace([]string{}); err != nil {
+		return err
+	}
+	if err := f.Value.Set(value); err != nil {
+		_ = sv.Replace(def)
+		return err
+	}
+	return nil
+}
@@ -2,4 +2,9 @@ module cobra
 
 go 1.21.3
 
-require github.com/spf13/pflag v1.0.5
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/inconshreveable/mousetrap v1.1.0
+	github.com/spf13/pflag v1.0.5
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,38 @@
+package cobra
+
+// RunFunc runs a command with its arguments, as RunE does.
+type RunFunc func(cmd *Command, args []string) error
+
+// Middleware wraps the run of a command. It returns the RunFunc to call in
+// place of next, which it may call any number of times, or not at all.
+type Middleware func(next RunFunc) RunFunc
+
+// UseMiddleware adds middleware around the Run or RunE of c and all its
+// descendants. The run is wrapped once the PersistentPreRun and PreRun hooks
+// have run and the required flags and flag groups have been validated, and
+// the PostRun and PersistentPostRun hooks only run once it returned.
+//
+// The middleware of the parents wraps the one of the children, and the
+// middleware added first wraps the one added later, so that for a root with
+// middleware A and B, and a child with C, the child runs as A(B(C(run))).
+func (c *Command) UseMiddleware(middleware ...Middleware) {
+	c.middleware = append(c.middleware, middleware...)
+}
+
+// runFunc returns the Run or RunE of c wrapped in the middleware of c and its parents.
+func (c *Command) runFunc() RunFunc {
+	run := func(cmd *Command, args []string) error {
+		if cmd.RunE != nil {
+			return cmd.RunE(cmd, args)
+		}
+		cmd.Run(cmd, args)
+		return nil
+	}
+
+	for p := c; p != nil; p = p.Parent() {
+		for i := len(p.middleware) - 1; i >= 0; i-- {
+			run = p.middleware[i](run)
+		}
+	}
+	return run
+}
@@ -0,0 +1,114 @@
+package cobra
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// recordingMiddleware records its name before and after calling next.
+func recordingMiddleware(name string, calls *[]string) Middleware {
+	return func(next RunFunc) RunFunc {
+		return func(cmd *Command, args []string) error {
+			*calls = append(*calls, name+">")
+			err := next(cmd, args)
+			*calls = append(*calls, "<"+name)
+			return err
+		}
+	}
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) func(*Command, []string) {
+		return func(*Command, []string) { calls = append(calls, name) }
+	}
+
+	rootCmd := &Command{Use: "root", PersistentPreRun: record("persistentPreRun")}
+	childCmd := &Command{Use: "child"}
+	grandchildCmd := &Command{Use: "grandchild", PreRun: record("preRun"), Run: record("run"), PostRun: record("postRun")}
+	siblingCmd := &Command{Use: "sibling", Run: record("run")}
+	childCmd.AddCommand(grandchildCmd)
+	rootCmd.AddCommand(childCmd, siblingCmd)
+
+	rootCmd.UseMiddleware(recordingMiddleware("A", &calls), recordingMiddleware("B", &calls))
+	childCmd.UseMiddleware(recordingMiddleware("C", &calls))
+
+	tests := []struct {
+		args  []string
+		calls []string
+	}{
+		{
+			[]string{"child", "grandchild"},
+			[]string{"persistentPreRun", "preRun", "A>", "B>", "C>", "run", "<C", "<B", "<A", "postRun"},
+		},
+		{
+			[]string{"sibling"},
+			[]string{"persistentPreRun", "A>", "B>", "run", "<B", "<A"},
+		},
+	}
+
+	for _, tc := range tests {
+		calls = nil
+		if _, err := executeCommand(rootCmd, tc.args...); err != nil {
+			t.Fatalf("Unexpected error for %v: %v", tc.args, err)
+		}
+		if !reflect.DeepEqual(calls, tc.calls) {
+			t.Errorf("For %v expected calls %v, got %v", tc.args, tc.calls, calls)
+		}
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	errDenied := errors.New("denied")
+	tests := []struct {
+		name    string
+		err     error
+		postRun bool
+	}{
+		{"error", errDenied, false},
+		{"no error", nil, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var ran, postRan bool
+			c := &Command{
+				Use:     "c",
+				Run:     func(*Command, []string) { ran = true },
+				PostRun: func(*Command, []string) { postRan = true },
+			}
+			c.UseMiddleware(func(next RunFunc) RunFunc {
+				return func(*Command, []string) error { return tc.err }
+			})
+
+			_, err := executeCommand(c)
+			if err != tc.err {
+				t.Errorf("Expected error %v, got %v", tc.err, err)
+			}
+			if ran {
+				t.Error("Expected the run to be skipped")
+			}
+			if postRan != tc.postRun {
+				t.Errorf("Expected the post-run hook to run: %v, got %v", tc.postRun, postRan)
+			}
+		})
+	}
+}
+
+func TestMiddlewareChangesArgs(t *testing.T) {
+	var got []string
+	c := &Command{Use: "c", Run: func(_ *Command, args []string) { got = args }}
+	c.UseMiddleware(func(next RunFunc) RunFunc {
+		return func(cmd *Command, args []string) error {
+			return next(cmd, append(args, "added"))
+		}
+	})
+
+	if _, err := executeCommand(c, "given"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"given", "added"}) {
+		t.Errorf("Expected args [given added], got %v", got)
+	}
+}
@@ -0,0 +1,109 @@
+package cobra
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	flag "github.com/spf13/pflag"
+)
+
+const redactedValue = "REDACTED"
+
+// executeRecover executes c, and returns a panic of it as a PanicError when
+// RecoverPanics is set on the root command. The finalizers deferred by
+// execute have run by then.
+func (c *Command) executeRecover(a []string) (err error) {
+	root := c.Root()
+	if !root.RecoverPanics {
+		return c.execute(a)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := &PanicError{
+				CommandPath: c.CommandPath(),
+				Args:        redactArgs(a, c.Flags()),
+				Value:       r,
+				Stack:       debug.Stack(),
+			}
+			if root.CrashReportDir != "" {
+				panicErr.ReportPath, _ = writeCrashReport(root.CrashReportDir, root.Name(), panicErr)
+			}
+			err = panicErr
+		}
+	}()
+	return c.execute(a)
+}
+
+func isSensitiveFlag(f *flag.Flag) bool {
+	return f != nil && f.Annotations[sensitiveFlagAnnotation] != nil
+}
+
+// redactArgs returns a copy of args with the values of the sensitive flags replaced.
+func redactArgs(args []string, flags *flag.FlagSet) []string {
+	redacted := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return append(redacted, args[i:]...)
+		case strings.HasPrefix(arg, "--"):
+			name, _, hasValue := strings.Cut(arg[2:], "=")
+			f := flags.Lookup(name)
+			if !isSensitiveFlag(f) {
+				break
+			}
+			if hasValue {
+				arg = "--" + name + "=" + redactedValue
+			} else if f.NoOptDefVal == "" && i+1 < len(args) {
+				redacted = append(redacted, arg)
+				i++
+				arg = redactedValue
+			}
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			// shorthands may be combined, the first one taking a value ends them
+			for j := 1; j < len(arg); j++ {
+				f := flags.ShorthandLookup(arg[j : j+1])
+				if f == nil || f.NoOptDefVal != "" {
+					continue
+				}
+				if !isSensitiveFlag(f) {
+					break
+				}
+				switch {
+				case j+1 < len(arg) && arg[j+1] == '=':
+					arg = arg[:j+2] + redactedValue
+				case j+1 < len(arg):
+					arg = arg[:j+1] + redactedValue
+				case i+1 < len(args):
+					redacted = append(redacted, arg)
+					i++
+					arg = redactedValue
+				}
+				break
+			}
+		}
+		redacted = append(redacted, arg)
+	}
+	return redacted
+}
+
+// writeCrashReport writes the report of e to a new file in dir, and returns its path.
+func writeCrashReport(dir, name string, e *PanicError) (string, error) {
+	f, err := os.CreateTemp(dir, name+"-crash-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, "Command: %s\n", e.CommandPath)
+	fmt.Fprintf(f, "Arguments: %q\n", e.Args)
+	fmt.Fprintf(f, "Time: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(f, "Go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(f, "Panic: %v\n\n%s", e.Value, e.Stack)
+	return f.Name(), nil
+}
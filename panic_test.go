@@ -0,0 +1,118 @@
+package cobra
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecoverPanics(t *testing.T) {
+	errBoom := errors.New("boom")
+	rootCmd := &Command{Use: "root", RecoverPanics: true}
+	childCmd := &Command{Use: "child", Run: func(*Command, []string) { panic(errBoom) }}
+	childCmd.Flags().String("user", "", "")
+	rootCmd.AddCommand(childCmd)
+
+	output, err := executeCommand(rootCmd, "child", "--user", "joe")
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected a PanicError, got %T: %v", err, err)
+	}
+	if panicErr.CommandPath != "root child" {
+		t.Errorf("Expected command path %q, got %q", "root child", panicErr.CommandPath)
+	}
+	if !reflect.DeepEqual(panicErr.Args, []string{"--user", "joe"}) {
+		t.Errorf("Expected args [--user joe], got %v", panicErr.Args)
+	}
+	if !errors.Is(err, errBoom) {
+		t.Error("Expected the PanicError to wrap the error the command panicked with")
+	}
+	if len(panicErr.Stack) == 0 {
+		t.Error("Expected the stack of the panic")
+	}
+	if panicErr.ReportPath != "" {
+		t.Errorf("Expected no crash report, got %s", panicErr.ReportPath)
+	}
+	if !strings.Contains(output, `Error: "root child" panicked: boom`) {
+		t.Errorf("Expected the panic to be printed, got:\n%s", output)
+	}
+}
+
+func TestRecoverPanicsDisabled(t *testing.T) {
+	c := &Command{Use: "c", Run: func(*Command, []string) { panic("boom") }}
+	expectPanic(t, "a command without RecoverPanics", func() { _, _ = executeCommand(c) })
+}
+
+func TestRedactArgs(t *testing.T) {
+	c := &Command{Use: "c", Run: emptyRun}
+	c.Flags().StringP("token", "t", "", "")
+	c.Flags().StringP("user", "u", "", "")
+	c.Flags().BoolP("verbose", "v", false, "")
+	if err := c.MarkFlagSensitive("token"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args     []string
+		redacted []string
+	}{
+		{[]string{"--token=secret", "arg"}, []string{"--token=REDACTED", "arg"}},
+		{[]string{"--token", "secret", "arg"}, []string{"--token", "REDACTED", "arg"}},
+		{[]string{"-t", "secret"}, []string{"-t", "REDACTED"}},
+		{[]string{"-tsecret"}, []string{"-tREDACTED"}},
+		{[]string{"-t=secret"}, []string{"-t=REDACTED"}},
+		{[]string{"-vt", "secret"}, []string{"-vt", "REDACTED"}},
+		{[]string{"--user", "joe", "-u", "ann"}, []string{"--user", "joe", "-u", "ann"}},
+		{[]string{"--", "--token", "literal"}, []string{"--", "--token", "literal"}},
+	}
+
+	for _, tc := range tests {
+		if got := redactArgs(tc.args, c.Flags()); !reflect.DeepEqual(got, tc.redacted) {
+			t.Errorf("For %v expected %v, got %v", tc.args, tc.redacted, got)
+		}
+	}
+}
+
+func TestCrashReport(t *testing.T) {
+	dir := t.TempDir()
+	rootCmd := &Command{
+		Use:            "root",
+		RecoverPanics:  true,
+		CrashReportDir: dir,
+		Run:            func(*Command, []string) { panic("boom") },
+	}
+	rootCmd.Flags().String("token", "", "")
+	if err := rootCmd.MarkFlagSensitive("token"); err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := executeCommand(rootCmd, "--token", "secret")
+	var panicErr *PanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected a PanicError, got %T: %v", err, err)
+	}
+	if !reflect.DeepEqual(panicErr.Args, []string{"--token", "REDACTED"}) {
+		t.Errorf("Expected the token to be redacted, got %v", panicErr.Args)
+	}
+	if panicErr.ReportPath == "" {
+		t.Fatal("Expected a crash report")
+	}
+	if !strings.Contains(output, "A crash report was written to "+panicErr.ReportPath) {
+		t.Errorf("Expected the report path to be printed, got:\n%s", output)
+	}
+
+	report, err := os.ReadFile(panicErr.ReportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Command: root\n", "Arguments: [\"--token\" \"REDACTED\"]\n", "Panic: boom\n"} {
+		if !strings.Contains(string(report), want) {
+			t.Errorf("Expected %q in the report, got:\n%s", want, report)
+		}
+	}
+	if strings.Contains(string(report), "secret") {
+		t.Errorf("Expected the token not to be in the report, got:\n%s", report)
+	}
+}
@@ -0,0 +1,274 @@
+package cobra
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ArgType is the type of a positional argument declared with Arg.
+type ArgType struct {
+	// Name is the name of the type shown in the help.
+	Name string
+	// Parse converts an argument to its value.
+	Parse func(arg string) (interface{}, error)
+	// Complete completes the argument. If it is nil, the ValidArgsFunction
+	// of the command is used instead.
+	Complete func(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective)
+}
+
+// Types of positional arguments. The values of String, File and Dir
+// arguments are strings, and the ones of Int, Float and Bool arguments are
+// int, float64 and bool values.
+var (
+	String = ArgType{Name: "string", Parse: parseStringArg}
+	Int    = ArgType{Name: "int", Parse: parseIntArg}
+	Float  = ArgType{Name: "float", Parse: parseFloatArg}
+	Bool   = ArgType{Name: "bool", Parse: parseBoolArg, Complete: completeBoolArg}
+	File   = ArgType{Name: "file", Parse: parseStringArg, Complete: completeFileArg}
+	Dir    = ArgType{Name: "dir", Parse: parseStringArg, Complete: completeDirArg}
+)
+
+func parseStringArg(arg string) (interface{}, error) {
+	return arg, nil
+}
+
+func parseIntArg(arg string) (interface{}, error) {
+	v, err := strconv.Atoi(arg)
+	return v, numError(err)
+}
+
+func parseFloatArg(arg string) (interface{}, error) {
+	v, err := strconv.ParseFloat(arg, 64)
+	return v, numError(err)
+}
+
+func parseBoolArg(arg string) (interface{}, error) {
+	v, err := strconv.ParseBool(arg)
+	return v, numError(err)
+}
+
+// numError strips the function and the input from the errors of strconv,
+// which ArgParseError already gives.
+func numError(err error) error {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return numErr.Err
+	}
+	return err
+}
+
+func completeBoolArg(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
+	return []string{"true", "false"}, ShellCompDirectiveNoFileComp
+}
+
+func completeFileArg(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
+	return nil, ShellCompDirectiveDefault
+}
+
+func completeDirArg(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
+	return nil, ShellCompDirectiveFilterDirs
+}
+
+// PositionalArg is a named and typed positional argument of a command,
+// declared with Arg.
+type PositionalArg struct {
+	cmd      *Command
+	name     string
+	typ      ArgType
+	usage    string
+	optional bool
+	variadic bool
+	complete func(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective)
+}
+
+// Arg declares the next positional argument of c, called name and of type typ.
+// The declared arguments are counted and parsed before the command runs, and
+// their values are returned by ArgValue. They also make the arguments part of
+// the UseLine if Use is only the name of the command, add an "Arguments:"
+// section to the usage, and are completed by their type.
+//
+// An Args validator may still be set, to validate the arguments further.
+//
+// Arg panics if the previous argument is variadic, or if the arguments
+// declared so far have a required argument after an optional one.
+func (c *Command) Arg(name string, typ ArgType) *PositionalArg {
+	c.checkPositionals()
+	if n := len(c.positionals); n > 0 && c.positionals[n-1].variadic {
+		panic(fmt.Sprintf("positional argument %q follows the variadic argument %q", name, c.positionals[n-1].name))
+	}
+	a := &PositionalArg{cmd: c, name: name, typ: typ}
+	c.positionals = append(c.positionals, a)
+	return a
+}
+
+// checkPositionals panics if a required positional argument of c is declared
+// after an optional one. As Optional is called after Arg, the order of the
+// last argument is checked by the next call to Arg, or when the arguments
+// are parsed.
+func (c *Command) checkPositionals() {
+	for i := 1; i < len(c.positionals); i++ {
+		if prev, a := c.positionals[i-1], c.positionals[i]; prev.optional && !a.optional {
+			panic(fmt.Sprintf("positional argument %q is required but follows the optional argument %q", a.name, prev.name))
+		}
+	}
+}
+
+// Optional makes the argument optional. The arguments after an optional one
+// must be optional too.
+//
+// Optional panics if a required argument was already declared after it.
+func (a *PositionalArg) Optional() *PositionalArg {
+	a.optional = true
+	a.cmd.checkPositionals()
+	return a
+}
+
+// Variadic makes the argument take all the remaining arguments, at least
+// one unless it is optional. It must be the last argument.
+//
+// Variadic panics if other arguments were already declared after it.
+func (a *PositionalArg) Variadic() *PositionalArg {
+	if ps := a.cmd.positionals; ps[len(ps)-1] != a {
+		panic(fmt.Sprintf("positional argument %q is variadic but is not the last argument", a.name))
+	}
+	a.variadic = true
+	return a
+}
+
+// Usage sets the description of the argument in the usage.
+func (a *PositionalArg) Usage(usage string) *PositionalArg {
+	a.usage = usage
+	return a
+}
+
+// Completion sets the function completing the argument, in place of the one of its type.
+func (a *PositionalArg) Completion(f func(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective)) *PositionalArg {
+	a.complete = f
+	return a
+}
+
+// Name returns the name of the argument.
+func (a *PositionalArg) Name() string {
+	return a.name
+}
+
+func (a *PositionalArg) completionFunc() func(cmd *Command, args []string, toComplete string) ([]string, ShellCompDirective) {
+	if a.complete != nil {
+		return a.complete
+	}
+	return a.typ.Complete
+}
+
+// useName returns how the argument is shown in the UseLine.
+func (a *PositionalArg) useName() string {
+	name := a.name
+	if a.variadic {
+		name += "..."
+	}
+	if a.optional {
+		return "[" + name + "]"
+	}
+	return "<" + name + ">"
+}
+
+// positionalAt returns the declared positional argument at index i, or nil if there is none.
+func (c *Command) positionalAt(i int) *PositionalArg {
+	if n := len(c.positionals); n > 0 && i >= n-1 && c.positionals[n-1].variadic {
+		return c.positionals[n-1]
+	}
+	if i < len(c.positionals) {
+		return c.positionals[i]
+	}
+	return nil
+}
+
+// positionalArity returns how many arguments the declared positional
+// arguments accept. max is -1 if there is no upper bound.
+func (c *Command) positionalArity() (min, max int) {
+	for _, a := range c.positionals {
+		if !a.optional {
+			min++
+		}
+	}
+	max = len(c.positionals)
+	if max > 0 && c.positionals[max-1].variadic {
+		max = -1
+	}
+	return min, max
+}
+
+// parsePositionals counts and parses args by the declared positional
+// arguments of c, and returns the values by name.
+func (c *Command) parsePositionals(args []string) (map[string]interface{}, error) {
+	c.checkPositionals()
+	min, max := c.positionalArity()
+	if len(args) < min || (max >= 0 && len(args) > max) {
+		return nil, &ArgCountError{Min: min, Max: max, Got: len(args)}
+	}
+
+	values := make(map[string]interface{}, len(c.positionals))
+	for i, arg := range args {
+		a := c.positionalAt(i)
+		v, err := a.typ.Parse(arg)
+		if err != nil {
+			return nil, &ArgParseError{Cmd: c, Name: a.name, Type: a.typ.Name, Arg: arg, Err: err}
+		}
+		if a.variadic {
+			list, _ := values[a.name].([]interface{})
+			values[a.name] = append(list, v)
+		} else {
+			values[a.name] = v
+		}
+	}
+	return values, nil
+}
+
+// ArgValue returns the value of the positional argument called name, as
+// parsed by its ArgType, or nil if it was not given. The value of a variadic
+// argument is a []interface{}.
+func (c *Command) ArgValue(name string) interface{} {
+	return c.argValues[name]
+}
+
+// HasPositionalArgs checks if the command declares positional arguments with Arg.
+func (c *Command) HasPositionalArgs() bool {
+	return len(c.positionals) > 0
+}
+
+// PositionalArgUsages returns a string containing the usage information
+// for the declared positional arguments of c.
+func (c *Command) PositionalArgUsages() string {
+	lines := make([]string, 0, len(c.positionals))
+	width := 0
+	for _, a := range c.positionals {
+		line := a.name + " " + a.typ.Name
+		if a.variadic {
+			line += "..."
+		}
+		if len(line) > width {
+			width = len(line)
+		}
+		lines = append(lines, line)
+	}
+
+	var sb strings.Builder
+	for i, a := range c.positionals {
+		usage := a.usage
+		if a.optional {
+			usage = strings.TrimSpace(usage + " (optional)")
+		}
+		fmt.Fprintf(&sb, "  %s   %s\n", rpad(lines[i], width), usage)
+	}
+	return sb.String()
+}
+
+// positionalUseLine returns the declared positional arguments as shown in the UseLine.
+func (c *Command) positionalUseLine() string {
+	names := make([]string, 0, len(c.positionals))
+	for _, a := range c.positionals {
+		names = append(names, a.useName())
+	}
+	return strings.Join(names, " ")
+}
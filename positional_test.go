@@ -0,0 +1,64 @@
+package cobra
+
+import (
+	"reflect"
+	"testing"
+)
+
+func expectPanic(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected %s to panic", name)
+		}
+	}()
+	f()
+}
+
+func TestArgDeclarationOrder(t *testing.T) {
+	c := &Command{Use: "c", Run: emptyRun}
+	c.Arg("src", String)
+	c.Arg("dst", String).Optional()
+	c.Arg("mode", Int).Optional()
+	c.Arg("extra", String)
+	expectPanic(t, "a required argument after an optional one", func() { c.Arg("more", String) })
+
+	c = &Command{Use: "c", Run: emptyRun}
+	c.Arg("src", String).Optional()
+	c.Arg("dst", String)
+	expectPanic(t, "executing with a required argument after an optional one", func() { _, _ = executeCommand(c, "a", "b") })
+
+	c = &Command{Use: "c", Run: emptyRun}
+	src := c.Arg("src", String)
+	c.Arg("dst", String)
+	expectPanic(t, "making an argument optional before a required one", func() { src.Optional() })
+
+	c = &Command{Use: "c", Run: emptyRun}
+	files := c.Arg("files", File)
+	c.Arg("dst", Dir)
+	expectPanic(t, "making an argument variadic before another one", func() { files.Variadic() })
+
+	c = &Command{Use: "c", Run: emptyRun}
+	c.Arg("files", File).Variadic()
+	expectPanic(t, "an argument after a variadic one", func() { c.Arg("extra", String) })
+}
+
+func TestOptionalArgs(t *testing.T) {
+	c := &Command{Use: "c", Run: emptyRun}
+	c.Arg("src", String)
+	c.Arg("count", Int).Optional()
+
+	if _, err := executeCommand(c, "a", "3"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := c.ArgValue("count"); !reflect.DeepEqual(got, 3) {
+		t.Errorf("Expected count 3, got %v", got)
+	}
+
+	if _, err := executeCommand(c); err == nil {
+		t.Error("Expected an error without the required argument")
+	}
+	if _, err := executeCommand(c, "a", "3", "b"); err == nil {
+		t.Error("Expected an error with too many arguments")
+	}
+}
@@ -0,0 +1,234 @@
+package cobra
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	progressInterval = 100 * time.Millisecond
+	progressBarWidth = 30
+	// progressLogStep is the percentage between two lines of a progress bar
+	// which is not rendered on a terminal.
+	progressLogStep = 10
+)
+
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// Progress reports the progress of a running command on its ErrOrStderr.
+// On a terminal it renders a spinner, or a progress bar once a total is set,
+// and elsewhere it logs a line for every message and every tenth of the total.
+// It renders nothing when the command has a "quiet" flag which is set.
+//
+// It stops rendering when the context of the command is done, and once the
+// Run or RunE of the command returned. Its methods may be called concurrently,
+// from the goroutines the command starts.
+type Progress struct {
+	out io.Writer
+	tty bool
+
+	mu        sync.Mutex
+	message   string
+	current   int64
+	total     int64
+	frame     int
+	lastLog   int64
+	stopped   bool
+	stop      chan struct{}
+	rendering sync.WaitGroup
+}
+
+// Progress returns the progress reporter of the running command c. It is
+// created by the first call, and may be called concurrently.
+func (c *Command) Progress() *Progress {
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+	if c.progress == nil {
+		c.progress = c.newProgress()
+	}
+	return c.progress
+}
+
+func (c *Command) newProgress() *Progress {
+	p := &Progress{out: c.ErrOrStderr(), stop: make(chan struct{})}
+	if f, ok := p.out.(*os.File); ok && isTerminal(f) {
+		p.tty = true
+	}
+	if q := c.Flags().Lookup("quiet"); q != nil && q.Value.Type() == "bool" && q.Value.String() == "true" {
+		p.stopped = true
+		return p
+	}
+
+	var done <-chan struct{}
+	if ctx := c.Context(); ctx != nil {
+		done = ctx.Done()
+	}
+
+	p.rendering.Add(1)
+	go p.run(done)
+	return p
+}
+
+func (p *Progress) run(done <-chan struct{}) {
+	defer p.rendering.Done()
+
+	var tick <-chan time.Time
+	if p.tty {
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-tick:
+			p.mu.Lock()
+			p.frame++
+			p.render()
+			p.mu.Unlock()
+		case <-done:
+			p.mu.Lock()
+			p.end("")
+			p.mu.Unlock()
+			return
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// SetMessage sets the message shown with the progress.
+func (p *Progress) SetMessage(format string, a ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.message = fmt.Sprintf(format, a...)
+	if !p.tty && !p.stopped {
+		fmt.Fprintln(p.out, p.message)
+	}
+	p.render()
+}
+
+// SetTotal sets the total amount of work, making the progress a bar.
+func (p *Progress) SetTotal(total int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.total = total
+	p.render()
+}
+
+// Add adds n to the amount of work done.
+func (p *Progress) Add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current += n
+	p.update()
+}
+
+// Set sets the amount of work done.
+func (p *Progress) Set(current int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current = current
+	p.update()
+}
+
+// Logf prints a line, above the progress on a terminal.
+func (p *Progress) Logf(format string, a ...interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		return
+	}
+	if p.tty {
+		fmt.Fprint(p.out, "\r\x1b[K")
+	}
+	fmt.Fprintf(p.out, format+"\n", a...)
+	p.render()
+}
+
+// Done stops the progress, leaving message as its last line if it is not empty.
+func (p *Progress) Done(message string) {
+	p.mu.Lock()
+	p.end(message)
+	p.mu.Unlock()
+	p.rendering.Wait()
+}
+
+func (p *Progress) update() {
+	if p.tty {
+		p.render()
+		return
+	}
+	if p.stopped || p.total <= 0 {
+		return
+	}
+	percent := p.percent()
+	if step := percent - percent%progressLogStep; step > p.lastLog {
+		p.lastLog = step
+		fmt.Fprintf(p.out, "%s%d%% (%d/%d)\n", p.prefix(), percent, p.current, p.total)
+	}
+}
+
+func (p *Progress) percent() int64 {
+	percent := p.current * 100 / p.total
+	if percent > 100 {
+		return 100
+	}
+	if percent < 0 {
+		return 0
+	}
+	return percent
+}
+
+func (p *Progress) prefix() string {
+	if p.message == "" {
+		return ""
+	}
+	return p.message + ": "
+}
+
+// render draws the progress line on a terminal. It must be called with p.mu held.
+func (p *Progress) render() {
+	if !p.tty || p.stopped {
+		return
+	}
+	var line string
+	if p.total > 0 {
+		percent := p.percent()
+		filled := int(percent * progressBarWidth / 100)
+		bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+		line = fmt.Sprintf("[%s] %3d%% %s", bar, percent, p.message)
+	} else {
+		line = fmt.Sprintf("%s %s", spinnerFrames[p.frame%len(spinnerFrames)], p.message)
+	}
+	fmt.Fprintf(p.out, "\r%s\x1b[K", line)
+}
+
+// end stops the rendering. It must be called with p.mu held.
+func (p *Progress) end(message string) {
+	if p.stopped {
+		return
+	}
+	p.stopped = true
+	close(p.stop)
+	if p.tty {
+		fmt.Fprint(p.out, "\r\x1b[K")
+	}
+	if message != "" {
+		fmt.Fprintln(p.out, message)
+	}
+}
+
+// endProgress stops the progress of c once its run returned.
+func (c *Command) endProgress() {
+	c.progressMu.Lock()
+	p := c.progress
+	c.progress = nil
+	c.progressMu.Unlock()
+	if p != nil {
+		p.Done("")
+	}
+}
@@ -0,0 +1,89 @@
+package cobra
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestProgressEndsWithExecution(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(c *Command, progress **Progress)
+	}{
+		{"run", func(c *Command, progress **Progress) {
+			c.Run = func(cmd *Command, args []string) { *progress = cmd.Progress() }
+		}},
+		{"pre-run error", func(c *Command, progress **Progress) {
+			c.PreRunE = func(cmd *Command, args []string) error {
+				*progress = cmd.Progress()
+				return errors.New("failed")
+			}
+		}},
+		{"middleware returning early", func(c *Command, progress **Progress) {
+			c.UseMiddleware(func(next RunFunc) RunFunc {
+				return func(cmd *Command, args []string) error {
+					*progress = cmd.Progress()
+					return errors.New("denied")
+				}
+			})
+		}},
+		{"panic", func(c *Command, progress **Progress) {
+			c.RecoverPanics = true
+			c.Run = func(cmd *Command, args []string) {
+				*progress = cmd.Progress()
+				panic("boom")
+			}
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Command{Use: "c", Run: emptyRun}
+			var progress *Progress
+			tc.setup(c, &progress)
+
+			_, _ = executeCommand(c)
+			if progress == nil {
+				t.Fatal("Expected the progress to be created")
+			}
+			progress.mu.Lock()
+			stopped := progress.stopped
+			progress.mu.Unlock()
+			if !stopped {
+				t.Error("Expected the progress to be stopped")
+			}
+			if c.progress != nil {
+				t.Error("Expected the progress of the command to be cleared")
+			}
+		})
+	}
+}
+
+func TestProgressConcurrentFirstCall(t *testing.T) {
+	var progresses [8]*Progress
+	c := &Command{Use: "c", Run: func(cmd *Command, args []string) {
+		var wg sync.WaitGroup
+		for i := range progresses {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				progresses[i] = cmd.Progress()
+				progresses[i].Add(1)
+			}(i)
+		}
+		wg.Wait()
+	}}
+
+	if _, err := executeCommand(c); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i, p := range progresses {
+		if p != progresses[0] {
+			t.Fatalf("Expected a single progress, got another one for call %d", i)
+		}
+	}
+	if progresses[0].current != int64(len(progresses)) {
+		t.Errorf("Expected %d done, got %d", len(progresses), progresses[0].current)
+	}
+}
@@ -0,0 +1,263 @@
+package cobra
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+)
+
+const sensitiveFlagAnnotation = "cobra_annotation_flag_sensitive"
+
+// MarkFlagSensitive marks the flag so that its value is masked when prompted for,
+// and redacted from the arguments of a PanicError.
+func (c *Command) MarkFlagSensitive(name string) error {
+	f := c.Flag(name)
+	if f == nil {
+		return fmt.Errorf("no such flag -%v", name)
+	}
+	if f.Annotations == nil {
+		f.Annotations = map[string][]string{}
+	}
+	f.Annotations[sensitiveFlagAnnotation] = []string{"true"}
+	return nil
+}
+
+// promptEnabled reports whether PromptMissing is set on c or one of its parents.
+func (c *Command) promptEnabled() bool {
+	for p := c; p != nil; p = p.Parent() {
+		if p.PromptMissing {
+			return true
+		}
+	}
+	return false
+}
+
+// prompter asks the user for values on the terminal c reads from.
+type prompter struct {
+	cmd  *Command
+	file *os.File
+	in   *bufio.Reader
+}
+
+// getPrompter returns the prompter of c, or nil if prompting is not enabled
+// or the input of c is not a terminal.
+func (c *Command) getPrompter() *prompter {
+	if !c.promptEnabled() {
+		return nil
+	}
+	f, ok := c.InOrStdin().(*os.File)
+	if !ok || !isTerminal(f) {
+		return nil
+	}
+	if c.prompter == nil || c.prompter.file != f {
+		c.prompter = &prompter{cmd: c, file: f, in: bufio.NewReader(f)}
+	}
+	return c.prompter
+}
+
+func (p *prompter) readLine(sensitive bool) (string, error) {
+	if sensitive {
+		if err := setEcho(p.file.Fd(), false); err != nil {
+			return "", err
+		}
+		defer func() {
+			_ = setEcho(p.file.Fd(), true)
+			fmt.Fprintln(p.cmd.ErrOrStderr())
+		}()
+	}
+
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if !sensitive {
+		line = strings.TrimSpace(line)
+	}
+	return line, nil
+}
+
+// ask prompts for a value until a non-empty one is given. If there are
+// choices, they are listed and may be picked by number, unless the answer
+// is one of the choices itself.
+func (p *prompter) ask(label string, choices []string, sensitive bool) (string, error) {
+	out := p.cmd.ErrOrStderr()
+	for {
+		if len(choices) > 0 {
+			fmt.Fprintf(out, "%s:\n", label)
+			for i, choice := range choices {
+				fmt.Fprintf(out, "  %d) %s\n", i+1, choice)
+			}
+			fmt.Fprint(out, "Enter a number or a value: ")
+		} else {
+			fmt.Fprintf(out, "%s: ", label)
+		}
+
+		line, err := p.readLine(sensitive)
+		if err != nil {
+			return "", err
+		}
+		if line == "" {
+			continue
+		}
+		if stringInSlice(line, choices) {
+			return line, nil
+		}
+		if n, err := strconv.Atoi(line); err == nil && n >= 1 && n <= len(choices) {
+			return choices[n-1], nil
+		}
+		return line, nil
+	}
+}
+
+// completionChoices returns the values of completions, without their
+// descriptions and the active help messages.
+func completionChoices(completions []string, directive ShellCompDirective) []string {
+	if directive&ShellCompDirectiveError != 0 {
+		return nil
+	}
+	var choices []string
+	for _, comp := range completions {
+		if strings.HasPrefix(comp, activeHelpMarker) {
+			continue
+		}
+		choices = append(choices, strings.SplitN(comp, "\t", 2)[0])
+	}
+	return choices
+}
+
+// argNames returns the names of the arguments in the Use line of c.
+func (c *Command) argNames() []string {
+	if c.HasPositionalArgs() {
+		names := make([]string, 0, len(c.positionals))
+		for _, a := range c.positionals {
+			names = append(names, a.name)
+		}
+		return names
+	}
+	fields := strings.Fields(c.Use)
+	if len(fields) < 2 {
+		return nil
+	}
+	var names []string
+	for _, field := range fields[1:] {
+		if field == "[flags]" {
+			continue
+		}
+		names = append(names, strings.Trim(field, "[]<>.|"))
+	}
+	return names
+}
+
+// requiredArgCount returns the number of arguments c requires. It is bounded
+// by the declared positional arguments, or by the ArgCountError validateErr
+// has if the Args validator rejected the count, or else taken as the number of
+// arguments of the Use line which are not in brackets.
+func (c *Command) requiredArgCount(validateErr error) int {
+	if arity := c.argsArity(); arity != nil {
+		return arity.Min
+	}
+	var countErr *ArgCountError
+	if errors.As(validateErr, &countErr) {
+		return countErr.Min
+	}
+	fields := strings.Fields(c.Use)
+	if len(fields) < 2 {
+		return 0
+	}
+	count, depth := 0, 0
+	for _, field := range fields[1:] {
+		if depth == 0 && !strings.HasPrefix(field, "[") && field != "|" {
+			count++
+		}
+		depth += strings.Count(field, "[") - strings.Count(field, "]")
+	}
+	return count
+}
+
+// promptMissingArgs prompts for the arguments missing to satisfy the Args
+// validator of c. It returns validateErr if it cannot prompt.
+func (c *Command) promptMissingArgs(args []string, validateErr error) ([]string, error) {
+	p := c.getPrompter()
+	if p == nil {
+		return args, validateErr
+	}
+	required := c.requiredArgCount(validateErr)
+	if len(args) >= required {
+		return args, validateErr
+	}
+
+	names := c.argNames()
+	for i := len(args); i < required; i++ {
+		label := fmt.Sprintf("Argument %d", i+1)
+		if i < len(names) && names[i] != "" {
+			label = names[i]
+		}
+
+		var choices []string
+		if len(c.ValidArgs) > 0 {
+			choices = completionChoices(c.ValidArgs, ShellCompDirectiveDefault)
+		} else if c.ValidArgsFunction != nil {
+			choices = completionChoices(c.ValidArgsFunction(c, args, ""))
+		}
+
+		value, err := p.ask(label, choices, false)
+		if err != nil {
+			return args, validateErr
+		}
+		args = append(args, c.resolveArgAliases([]string{value})...)
+	}
+	return args, c.ValidateArgs(args)
+}
+
+// promptMissingFlags prompts for the required flags which are not set.
+// It returns validateErr if it cannot prompt.
+func (c *Command) promptMissingFlags(args []string, validateErr error) error {
+	p := c.getPrompter()
+	if p == nil {
+		return validateErr
+	}
+
+	var missing []*flag.Flag
+	c.Flags().VisitAll(func(f *flag.Flag) {
+		required, found := f.Annotations[BashCompOneRequiredFlag]
+		if found && len(required) > 0 && required[0] == "true" && !flagHasValue(f) {
+			missing = append(missing, f)
+		}
+	})
+
+	for _, f := range missing {
+		label := fmt.Sprintf("--%s", f.Name)
+		if _, usage := flag.UnquoteUsage(f); usage != "" {
+			label = fmt.Sprintf("%s (--%s)", usage, f.Name)
+		}
+		sensitive := f.Annotations[sensitiveFlagAnnotation] != nil
+
+		var choices []string
+		if !sensitive {
+			if compFn, found := c.GetFlagCompletionFunc(f.Name); found {
+				choices = completionChoices(compFn(c, args, ""))
+			}
+		}
+
+		for {
+			value, err := p.ask(label, choices, sensitive)
+			if err != nil {
+				return validateErr
+			}
+			if err := c.Flags().Set(f.Name, value); err != nil {
+				fmt.Fprintln(c.ErrOrStderr(), err)
+				continue
+			}
+			setFlagSource(c.Flags(), f.Name, FlagSourcePrompt)
+			break
+		}
+	}
+	return c.ValidateRequiredFlags()
+}
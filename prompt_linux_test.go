@@ -0,0 +1,210 @@
+package cobra
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// openPTY opens a pseudo terminal, and returns its master side, which the
+// test types on, and its slave side, which a command reads from.
+func openPTY(t *testing.T) (master, slave *os.File) {
+	t.Helper()
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		t.Skipf("No pseudo terminal: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+
+	var unlock int32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); errno != 0 {
+		t.Skipf("Cannot unlock the pseudo terminal: %v", errno)
+	}
+	var n uint32
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, master.Fd(), syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); errno != 0 {
+		t.Skipf("Cannot name the pseudo terminal: %v", errno)
+	}
+	slave, err = os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("Cannot open the pseudo terminal: %v", err)
+	}
+	t.Cleanup(func() { slave.Close() })
+	return master, slave
+}
+
+// readEcho returns what the terminal echoed of the input typed on master.
+func readEcho(t *testing.T, master *os.File) string {
+	t.Helper()
+	if err := master.SetReadDeadline(time.Now().Add(100 * time.Millisecond)); err != nil {
+		t.Skipf("Cannot read the pseudo terminal: %v", err)
+	}
+	var out bytes.Buffer
+	buf := make([]byte, 1024)
+	for {
+		n, err := master.Read(buf)
+		out.Write(buf[:n])
+		if err != nil {
+			return out.String()
+		}
+	}
+}
+
+func TestPromptMissingArgChoices(t *testing.T) {
+	master, slave := openPTY(t)
+
+	var got []string
+	c := &Command{
+		Use:  "paint <color>",
+		Args: ExactArgs(1),
+		ValidArgsFunction: func(*Command, []string, string) ([]string, ShellCompDirective) {
+			return []string{"red\tthe color of fire", "blue"}, ShellCompDirectiveNoFileComp
+		},
+		PromptMissing: true,
+		Run:           func(_ *Command, args []string) { got = args },
+	}
+	stderr := new(bytes.Buffer)
+	c.SetIn(slave)
+	c.SetErr(stderr)
+	c.SetArgs(nil)
+
+	if _, err := master.WriteString("2\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, []string{"blue"}) {
+		t.Errorf("Expected args [blue], got %v", got)
+	}
+	expected := "color:\n  1) red\n  2) blue\nEnter a number or a value: "
+	if stderr.String() != expected {
+		t.Errorf("Expected prompt %q, got %q", expected, stderr.String())
+	}
+}
+
+func TestPromptMissingArgOfUseLine(t *testing.T) {
+	master, slave := openPTY(t)
+
+	var got []string
+	c := &Command{
+		Use: "greet <name> [greeting]",
+		Args: func(_ *Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("missing name")
+			}
+			return nil
+		},
+		PromptMissing: true,
+		Run:           func(_ *Command, args []string) { got = args },
+	}
+	stderr := new(bytes.Buffer)
+	c.SetIn(slave)
+	c.SetErr(stderr)
+	c.SetArgs(nil)
+
+	if _, err := master.WriteString("joe\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, []string{"joe"}) {
+		t.Errorf("Expected args [joe], got %v", got)
+	}
+	if stderr.String() != "name: " {
+		t.Errorf("Expected prompt %q, got %q", "name: ", stderr.String())
+	}
+}
+
+func TestPromptMissingArgsOfCount(t *testing.T) {
+	master, slave := openPTY(t)
+
+	var got []string
+	c := &Command{
+		Use:           "pair",
+		Args:          ExactArgs(2),
+		PromptMissing: true,
+		Run:           func(_ *Command, args []string) { got = args },
+	}
+	c.SetIn(slave)
+	c.SetErr(new(bytes.Buffer))
+	c.SetArgs([]string{"a"})
+
+	if _, err := master.WriteString("b\n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Expected args [a b], got %v", got)
+	}
+}
+
+func TestPromptSensitiveFlagIsMasked(t *testing.T) {
+	master, slave := openPTY(t)
+
+	c := &Command{Use: "login", PromptMissing: true, Run: emptyRun}
+	c.Flags().String("token", "", "the token")
+	if err := c.MarkFlagRequired("token"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.MarkFlagSensitive("token"); err != nil {
+		t.Fatal(err)
+	}
+	stderr := new(bytes.Buffer)
+	c.SetIn(slave)
+	c.SetErr(stderr)
+	c.SetArgs(nil)
+
+	// the token is typed once the echo is off, or after a second if it is
+	// never turned off
+	typed := make(chan error, 1)
+	go func() {
+		deadline := time.Now().Add(time.Second)
+		for {
+			term, err := getTermios(slave.Fd())
+			if err != nil {
+				typed <- err
+				return
+			}
+			if term.Lflag&syscall.ECHO == 0 || time.Now().After(deadline) {
+				_, err = master.WriteString("hunter2\n")
+				typed <- err
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	if err := c.Execute(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := <-typed; err != nil {
+		t.Fatal(err)
+	}
+
+	if token, _ := c.Flags().GetString("token"); token != "hunter2" {
+		t.Errorf("Expected token %q, got %q", "hunter2", token)
+	}
+	if source := c.FlagSource("token"); source != FlagSourcePrompt {
+		t.Errorf("Expected source %q, got %q", FlagSourcePrompt, source)
+	}
+	if echo := readEcho(t, master); strings.Contains(echo, "hunter2") {
+		t.Errorf("Expected the token not to be echoed, got %q", echo)
+	}
+	if term, err := getTermios(slave.Fd()); err != nil || term.Lflag&syscall.ECHO == 0 {
+		t.Errorf("Expected the echo to be turned back on, got error %v", err)
+	}
+	if stderr.String() != "the token (--token): \n" {
+		t.Errorf("Expected prompt %q, got %q", "the token (--token): \n", stderr.String())
+	}
+}
@@ -0,0 +1,103 @@
+package cobra
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPromptAskChoices(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"1\n", "1"},
+		{"2\n", "1"},
+		{"3\n", "3"},
+		{"4\n", "4"},
+		{"\nother\n", "other"},
+	}
+
+	for _, tc := range tests {
+		c := &Command{Use: "c"}
+		c.SetErr(new(bytes.Buffer))
+		p := &prompter{cmd: c, in: bufio.NewReader(strings.NewReader(tc.input))}
+
+		got, err := p.ask("value", []string{"3", "1"}, false)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got != tc.want {
+			t.Errorf("For input %q expected %q, got %q", tc.input, tc.want, got)
+		}
+	}
+}
+
+func TestFlagRequirementsSeePromptedArgs(t *testing.T) {
+	c := &Command{Use: "c", Run: emptyRun}
+	c.Flags().Bool("force", false, "")
+	c.MarkFlagRequiredWithArgs("force", "delete")
+	if err := c.ParseFlags(nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if err := c.ValidateFlagGroups(); err != nil {
+		t.Errorf("Unexpected error without arguments: %v", err)
+	}
+	// the arguments prompted for are not in c.Flags().Args()
+	if err := c.validateFlagGroups([]string{"delete"}); err == nil {
+		t.Error("Expected an error for the prompted argument delete")
+	}
+}
+
+func TestPromptMissingWithoutTerminal(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString("value\n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	inputs := []struct {
+		name string
+		in   io.Reader
+	}{
+		{"reader", strings.NewReader("value\n")},
+		{"file", file},
+	}
+	for _, input := range inputs {
+		t.Run(input.name, func(t *testing.T) {
+			c := &Command{Use: "c", PromptMissing: true, Run: emptyRun}
+			c.Flags().String("name", "", "")
+			if err := c.MarkFlagRequired("name"); err != nil {
+				t.Fatal(err)
+			}
+			stderr := new(bytes.Buffer)
+			c.SetIn(input.in)
+			c.SetOutput(new(bytes.Buffer))
+			c.SetErr(stderr)
+			c.SetArgs(nil)
+
+			err := c.Execute()
+			var requiredErr *RequiredFlagsError
+			if !errors.As(err, &requiredErr) {
+				t.Fatalf("Expected a RequiredFlagsError, got %T: %v", err, err)
+			}
+			if strings.Contains(stderr.String(), "--name: ") {
+				t.Errorf("Expected no prompt, got %q", stderr.String())
+			}
+			if rest, _ := io.ReadAll(input.in); string(rest) != "value\n" {
+				t.Errorf("Expected the input not to be read, got %q left", rest)
+			}
+		})
+	}
+}
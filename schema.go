@@ -0,0 +1,281 @@
+package cobra
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// SchemaRequestCmd is the name of the hidden command that dumps the command tree.
+	SchemaRequestCmd = "__schema"
+)
+
+// DumpFormat is the output format of DumpTree.
+type DumpFormat string
+
+const (
+	DumpFormatJSON DumpFormat = "json"
+	DumpFormatYAML DumpFormat = "yaml"
+)
+
+// CommandSchema is the machine-readable description of a command.
+type CommandSchema struct {
+	Name           string            `json:"name"`
+	Path           string            `json:"path"`
+	Use            string            `json:"use"`
+	Aliases        []string          `json:"aliases,omitempty"`
+	Short          string            `json:"short,omitempty"`
+	Long           string            `json:"long,omitempty"`
+	Example        string            `json:"example,omitempty"`
+	Annotations    map[string]string `json:"annotations,omitempty"`
+	GroupID        string            `json:"groupID,omitempty"`
+	Deprecated     string            `json:"deprecated,omitempty"`
+	Hidden         bool              `json:"hidden"`
+	Runnable       bool              `json:"runnable"`
+	Args           *ArgsSchema       `json:"args,omitempty"`
+	ValidArgs      []string          `json:"validArgs,omitempty"`
+	Flags          []FlagSchema      `json:"flags,omitempty"`
+	InheritedFlags []FlagSchema      `json:"inheritedFlags,omitempty"`
+	FlagGroups     []FlagGroupSchema `json:"flagGroups,omitempty"`
+	Commands       []CommandSchema   `json:"commands,omitempty"`
+}
+
+// ArgsSchema describes how many positional arguments a command accepts.
+// Max is -1 if there is no upper bound.
+type ArgsSchema struct {
+	Min int `json:"min"`
+	Max int `json:"max"`
+}
+
+// FlagSchema is the machine-readable description of a flag.
+type FlagSchema struct {
+	Name       string            `json:"name"`
+	Shorthand  string            `json:"shorthand,omitempty"`
+	Type       string            `json:"type"`
+	Default    string            `json:"default"`
+	Usage      string            `json:"usage,omitempty"`
+	Deprecated string            `json:"deprecated,omitempty"`
+	Hidden     bool              `json:"hidden"`
+	Persistent bool              `json:"persistent"`
+	Required   bool              `json:"required"`
+	Groups     []FlagGroupSchema `json:"groups,omitempty"`
+}
+
+// FlagGroupSchema describes a flag group a flag belongs to, or a requirement
+// marked on it. Kind is one of the FlagGroup constants.
+//
+// Flags are the flags of the group, or for FlagGroupRequires and
+// FlagGroupRequiresValue the flags required. Max is the number of flags of a
+// FlagGroupAtMost group which may be set, Value the value required by
+// FlagGroupRequiresValue, and Args the arguments making the flag required
+// for FlagGroupRequiredWithArg.
+type FlagGroupSchema struct {
+	Kind  string   `json:"kind"`
+	Flags []string `json:"flags,omitempty"`
+	Max   int      `json:"max,omitempty"`
+	Value string   `json:"value,omitempty"`
+	Args  []string `json:"args,omitempty"`
+}
+
+var flagGroupKinds = []struct {
+	annotation string
+	kind       string
+}{
+	{requiredAsGroupAnnotation, FlagGroupRequiredTogether},
+	{oneRequiredAnnotation, FlagGroupOneRequired},
+	{mutuallyExclusiveAnnotation, FlagGroupMutuallyExclusive},
+	{exactlyOneAnnotation, FlagGroupExactlyOne},
+	{atMostAnnotation, FlagGroupAtMost},
+}
+
+// flagGroupSchema describes group, as stored under annotation.
+func flagGroupSchema(annotation, kind, group string) FlagGroupSchema {
+	if annotation == atMostAnnotation {
+		n, flagnames := splitAtMostGroup(group)
+		return FlagGroupSchema{Kind: kind, Flags: flagnames, Max: n}
+	}
+	return FlagGroupSchema{Kind: kind, Flags: strings.Split(group, " ")}
+}
+
+// persistentFlagGroupSchemas describes the groups marked by the
+// MarkPersistentFlags functions on c and its parents, which are enforced on c.
+func (c *Command) persistentFlagGroupSchemas() []FlagGroupSchema {
+	var groups []FlagGroupSchema
+	for _, k := range flagGroupKinds {
+		for _, group := range c.inheritedFlagGroups(k.annotation) {
+			groups = append(groups, flagGroupSchema(k.annotation, k.kind, group))
+		}
+	}
+	return groups
+}
+
+// Schema returns the machine-readable description of c and all its descendants.
+func (c *Command) Schema() CommandSchema {
+	c.mergePersistentFlags()
+
+	s := CommandSchema{
+		Name:        c.Name(),
+		Path:        c.CommandPath(),
+		Use:         c.Use,
+		Aliases:     c.Aliases,
+		Short:       c.Short,
+		Long:        c.Long,
+		Example:     c.Example,
+		Annotations: c.Annotations,
+		GroupID:     c.GroupID,
+		Deprecated:  c.Deprecated,
+		Hidden:      c.Hidden,
+		Runnable:    c.Runnable(),
+		Args:        c.argsArity(),
+	}
+	for _, v := range c.ValidArgs {
+		s.ValidArgs = append(s.ValidArgs, strings.SplitN(v, "\t", 2)[0])
+	}
+
+	pflags := c.PersistentFlags()
+	c.LocalFlags().VisitAll(func(f *flag.Flag) {
+		s.Flags = append(s.Flags, flagSchema(f, pflags.Lookup(f.Name) == f))
+	})
+	c.InheritedFlags().VisitAll(func(f *flag.Flag) {
+		s.InheritedFlags = append(s.InheritedFlags, flagSchema(f, true))
+	})
+	s.FlagGroups = c.persistentFlagGroupSchemas()
+
+	for _, sub := range c.Commands() {
+		if sub.Name() == SchemaRequestCmd || sub.Name() == ShellCompRequestCmd {
+			continue
+		}
+		s.Commands = append(s.Commands, sub.Schema())
+	}
+	return s
+}
+
+// argsArity returns the bounds the declared positional arguments put on the
+// number of arguments, or nil if none are declared. The Args validator is
+// not counted, as it cannot be inspected without running it.
+func (c *Command) argsArity() *ArgsSchema {
+	if !c.HasPositionalArgs() {
+		return nil
+	}
+	min, max := c.positionalArity()
+	return &ArgsSchema{Min: min, Max: max}
+}
+
+func flagSchema(f *flag.Flag, persistent bool) FlagSchema {
+	s := FlagSchema{
+		Name:       f.Name,
+		Shorthand:  f.Shorthand,
+		Type:       f.Value.Type(),
+		Default:    f.DefValue,
+		Usage:      f.Usage,
+		Deprecated: f.Deprecated,
+		Hidden:     f.Hidden,
+		Persistent: persistent,
+	}
+	if required, found := f.Annotations[BashCompOneRequiredFlag]; found && len(required) > 0 && required[0] == "true" {
+		s.Required = true
+	}
+	for _, k := range flagGroupKinds {
+		for _, group := range f.Annotations[k.annotation] {
+			s.Groups = append(s.Groups, flagGroupSchema(k.annotation, k.kind, group))
+		}
+	}
+	if required := f.Annotations[requiresAnnotation]; len(required) > 0 {
+		s.Groups = append(s.Groups, FlagGroupSchema{Kind: FlagGroupRequires, Flags: required})
+	}
+	for _, requirement := range f.Annotations[requiresValueAnnotation] {
+		name, value := splitRequiredValue(requirement)
+		s.Groups = append(s.Groups, FlagGroupSchema{Kind: FlagGroupRequiresValue, Flags: []string{name}, Value: value})
+	}
+	if args := f.Annotations[requiredWithArgAnnotation]; len(args) > 0 {
+		s.Groups = append(s.Groups, FlagGroupSchema{Kind: FlagGroupRequiredWithArg, Args: args})
+	}
+	return s
+}
+
+// DumpTree writes the description of c and all its descendants to w,
+// in the given format.
+func (c *Command) DumpTree(w io.Writer, format DumpFormat) error {
+	data, err := json.MarshalIndent(c.Schema(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case DumpFormatJSON:
+		data = append(data, '\n')
+	case DumpFormatYAML:
+		if data, err = jsonToYAML(data); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported dump format %q", format)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func (c *Command) initSchemaCmd(args []string) {
+	if !EnableSchemaCommand {
+		return
+	}
+
+	schemaCmd := &Command{
+		Use:                   fmt.Sprintf("%s [json|yaml]", SchemaRequestCmd),
+		DisableFlagsInUseLine: true,
+		Hidden:                true,
+		Args:                  RangeArgs(0, 1),
+		ValidArgs:             []string{string(DumpFormatJSON), string(DumpFormatYAML)},
+		Short:                 "Dump the command tree in a machine-readable format",
+		RunE: func(cmd *Command, args []string) error {
+			format := DumpFormatJSON
+			if len(args) > 0 {
+				format = DumpFormat(args[0])
+			}
+			return cmd.Root().DumpTree(cmd.OutOrStdout(), format)
+		},
+	}
+
+	c.AddCommand(schemaCmd)
+	subCmd, _, err := c.Find(args)
+	if err != nil || subCmd.Name() != SchemaRequestCmd {
+		c.RemoveCommand(schemaCmd)
+	}
+}
+
+// jsonToYAML converts a JSON document to block style YAML, keeping the
+// order of object keys.
+func jsonToYAML(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	clearYAMLStyle(&node)
+
+	buf := new(bytes.Buffer)
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// clearYAMLStyle drops the flow style and the quotes a JSON document is
+// decoded with, so that the encoder picks the block style and quotes only
+// the strings which need it.
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
@@ -0,0 +1,141 @@
+package cobra
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSchemaArgsArity(t *testing.T) {
+	tests := []struct {
+		name  string
+		cmd   func() *Command
+		arity *ArgsSchema
+	}{
+		{"no validator", func() *Command { return &Command{Use: "c", Run: emptyRun} }, nil},
+		{"validator", func() *Command { return &Command{Use: "c", Args: ExactArgs(2), Run: emptyRun} }, nil},
+		{"positionals", func() *Command {
+			c := &Command{Use: "c", Run: emptyRun}
+			c.Arg("name", String)
+			c.Arg("files", File).Variadic()
+			return c
+		}, &ArgsSchema{Min: 2, Max: -1}},
+		{"positionals and validator", func() *Command {
+			c := &Command{Use: "c", Args: MaximumNArgs(4), Run: emptyRun}
+			c.Arg("name", String)
+			c.Arg("files", File).Variadic()
+			return c
+		}, &ArgsSchema{Min: 2, Max: -1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.cmd().Schema().Args; !reflect.DeepEqual(got, tc.arity) {
+				t.Errorf("Expected arity %+v, got %+v", tc.arity, got)
+			}
+		})
+	}
+}
+
+func TestDumpTreeYAML(t *testing.T) {
+	c := &Command{
+		Use:     "c",
+		Example: "c --x\n  c --y",
+		Run:     emptyRun,
+		Annotations: map[string]string{
+			"a: b": "c", "#d": "e", "yes": "true", "n": "1", "null": "", "quote": `"'`,
+		},
+	}
+	c.Flags().String("x", "~", "")
+
+	jsonBuf := new(bytes.Buffer)
+	if err := c.DumpTree(jsonBuf, DumpFormatJSON); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	yamlBuf := new(bytes.Buffer)
+	if err := c.DumpTree(yamlBuf, DumpFormatYAML); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var fromJSON, fromYAML map[string]interface{}
+	if err := json.Unmarshal(jsonBuf.Bytes(), &fromJSON); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := yaml.Unmarshal(yamlBuf.Bytes(), &fromYAML); err != nil {
+		t.Fatalf("Unexpected error: %v\n%s", err, yamlBuf)
+	}
+	if !reflect.DeepEqual(fromJSON, fromYAML) {
+		t.Errorf("Expected the YAML dump to hold the JSON dump %v, got %v", fromJSON, fromYAML)
+	}
+
+	out := yamlBuf.String()
+	if !strings.HasPrefix(out, "name: c\npath: c\nuse: c\n") {
+		t.Errorf("Expected a block style dump keeping the order of the keys, got:\n%s", out)
+	}
+}
+
+func TestSchemaFlagRequirements(t *testing.T) {
+	c := &Command{Use: "c", Run: emptyRun}
+	for _, name := range []string{"a", "b", "c", "out", "format"} {
+		c.Flags().String(name, "", "")
+	}
+	c.MarkFlagsExactlyOne("a", "b")
+	c.MarkFlagsAtMost(2, "a", "b", "c")
+	c.MarkFlagRequires("out", "a", "b")
+	c.MarkFlagRequiresValue("out", "format", "json")
+	c.MarkFlagRequiredWithArgs("format", "export", "dump")
+
+	groups := map[string][]FlagGroupSchema{}
+	for _, f := range c.Schema().Flags {
+		groups[f.Name] = f.Groups
+	}
+
+	tests := []struct {
+		flag   string
+		groups []FlagGroupSchema
+	}{
+		{"c", []FlagGroupSchema{{Kind: FlagGroupAtMost, Flags: []string{"a", "b", "c"}, Max: 2}}},
+		{"a", []FlagGroupSchema{
+			{Kind: FlagGroupExactlyOne, Flags: []string{"a", "b"}},
+			{Kind: FlagGroupAtMost, Flags: []string{"a", "b", "c"}, Max: 2},
+		}},
+		{"out", []FlagGroupSchema{
+			{Kind: FlagGroupRequires, Flags: []string{"a", "b"}},
+			{Kind: FlagGroupRequiresValue, Flags: []string{"format"}, Value: "json"},
+		}},
+		{"format", []FlagGroupSchema{{Kind: FlagGroupRequiredWithArg, Args: []string{"export", "dump"}}}},
+	}
+	for _, tc := range tests {
+		if !reflect.DeepEqual(groups[tc.flag], tc.groups) {
+			t.Errorf("Expected groups of --%s %+v, got %+v", tc.flag, tc.groups, groups[tc.flag])
+		}
+	}
+}
+
+func TestSchemaPersistentFlagGroups(t *testing.T) {
+	rootCmd := &Command{Use: "root", TraverseChildren: true}
+	rootCmd.PersistentFlags().String("a", "", "")
+	rootCmd.PersistentFlags().String("b", "", "")
+	rootCmd.MarkPersistentFlagsMutuallyExclusive("a", "b")
+	childCmd := &Command{Use: "child", Run: emptyRun}
+	childCmd.PersistentFlags().String("c", "", "")
+	rootCmd.AddCommand(childCmd)
+	childCmd.MarkPersistentFlagsAtMost(1, "a", "c")
+
+	s := rootCmd.Schema()
+	rootGroups := []FlagGroupSchema{{Kind: FlagGroupMutuallyExclusive, Flags: []string{"a", "b"}}}
+	if !reflect.DeepEqual(s.FlagGroups, rootGroups) {
+		t.Errorf("Expected groups of root %+v, got %+v", rootGroups, s.FlagGroups)
+	}
+	childGroups := []FlagGroupSchema{
+		{Kind: FlagGroupMutuallyExclusive, Flags: []string{"a", "b"}},
+		{Kind: FlagGroupAtMost, Flags: []string{"a", "c"}, Max: 1},
+	}
+	if !reflect.DeepEqual(s.Commands[0].FlagGroups, childGroups) {
+		t.Errorf("Expected groups of child %+v, got %+v", childGroups, s.Commands[0].FlagGroups)
+	}
+}
@@ -0,0 +1,407 @@
+package cobra
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RunShell reads command lines from InOrStdin and executes each of them
+// against the command tree of c, as if it was given on the command line,
+// until the input ends, "exit" is entered or ctx is done. The state of the
+// tree is reset, as by ResetState, before every line. Errors of the commands
+// are reported as by Execute and do not end the shell.
+//
+// When the input is a terminal, lines are edited with tab completion,
+// as provided by ValidArgs and the completion functions, and history.
+func (c *Command) RunShell(ctx context.Context) error {
+	root := c.Root()
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+
+	r := newShellReader(root)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line, err := r.readLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		args, err := splitShellLine(line)
+		if err != nil {
+			root.PrintErrln("Error:", err.Error())
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+		if len(args) == 1 && (args[0] == "exit" || args[0] == "quit") {
+			if cmd, _, err := root.Find(args); err != nil || cmd == root {
+				return nil
+			}
+		}
+
+		if err := root.ResetState(); err != nil {
+			root.PrintErrln("Error:", err.Error())
+			continue
+		}
+		root.SetArgs(args)
+		_, _ = root.ExecuteContextC(ctx)
+	}
+}
+
+// splitShellLine splits line into arguments like a POSIX shell, with single
+// and double quotes and backslash escapes.
+func splitShellLine(line string) ([]string, error) {
+	words, _, open := splitShellWords(line)
+	if open {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	return words, nil
+}
+
+// splitShellWords splits line into words. It also returns the position where
+// the last word starts, or len(line) if the line ends with a blank, and
+// whether a quote or an escape is left open.
+func splitShellWords(line string) (words []string, lastStart int, open bool) {
+	var word strings.Builder
+	var quote rune
+	inWord, escaped := false, false
+
+	for i, ch := range line {
+		switch {
+		case escaped:
+			if quote == '"' && !strings.ContainsRune("\"\\$`", ch) {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(ch)
+			escaped = false
+		case quote == '\'':
+			if ch == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(ch)
+			}
+		case quote == '"':
+			switch ch {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				word.WriteRune(ch)
+			}
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			if !inWord {
+				inWord = true
+				lastStart = i
+			}
+			switch ch {
+			case '\'', '"':
+				quote = ch
+			case '\\':
+				escaped = true
+			default:
+				word.WriteRune(ch)
+			}
+		}
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	} else {
+		lastStart = len(line)
+	}
+	return words, lastStart, quote != 0 || escaped
+}
+
+// shellEscape escapes the characters of s which a shell line would interpret.
+func shellEscape(s string) string {
+	var b strings.Builder
+	for _, ch := range s {
+		if strings.ContainsRune(" \t\\'\"$`&|;<>()*?!#~", ch) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
+
+// shellReader reads the lines of RunShell.
+type shellReader struct {
+	cmd     *Command
+	prompt  string
+	term    *os.File
+	in      *bufio.Reader
+	history []string
+}
+
+func newShellReader(root *Command) *shellReader {
+	r := &shellReader{cmd: root, prompt: root.Name() + "> "}
+	in := root.InOrStdin()
+	if f, ok := in.(*os.File); ok && isTerminal(f) {
+		r.term = f
+	}
+	r.in = bufio.NewReader(in)
+	return r
+}
+
+func (r *shellReader) readLine() (string, error) {
+	if r.term != nil {
+		if restore, err := makeRaw(r.term.Fd()); err == nil {
+			defer restore()
+			return r.editLine()
+		}
+		r.cmd.Print(r.prompt)
+	}
+
+	line, err := r.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// editLine reads a line from the terminal in raw mode.
+func (r *shellReader) editLine() (string, error) {
+	out := r.cmd.OutOrStdout()
+	var buf []rune
+	pos := 0
+	histPos := len(r.history)
+
+	redraw := func() {
+		fmt.Fprintf(out, "\r%s%s\x1b[K", r.prompt, string(buf))
+		if n := len(buf) - pos; n > 0 {
+			fmt.Fprintf(out, "\x1b[%dD", n)
+		}
+	}
+	fromHistory := func(i int) {
+		histPos = i
+		buf = nil
+		if i < len(r.history) {
+			buf = []rune(r.history[i])
+		}
+		pos = len(buf)
+	}
+
+	redraw()
+	for {
+		ch, _, err := r.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+
+		switch ch {
+		case '\r', '\n':
+			fmt.Fprint(out, "\r\n")
+			line := string(buf)
+			if strings.TrimSpace(line) != "" && (len(r.history) == 0 || r.history[len(r.history)-1] != line) {
+				r.history = append(r.history, line)
+			}
+			return line, nil
+		case 3: // Ctrl-C drops the line
+			fmt.Fprint(out, "^C\r\n")
+			fromHistory(len(r.history))
+		case 4: // Ctrl-D ends the input on an empty line
+			if len(buf) == 0 {
+				fmt.Fprint(out, "\r\n")
+				return "", io.EOF
+			}
+			if pos < len(buf) {
+				buf = append(buf[:pos], buf[pos+1:]...)
+			}
+		case 127, 8:
+			if pos > 0 {
+				buf = append(buf[:pos-1], buf[pos:]...)
+				pos--
+			}
+		case 1: // Ctrl-A
+			pos = 0
+		case 5: // Ctrl-E
+			pos = len(buf)
+		case 11: // Ctrl-K
+			buf = buf[:pos]
+		case 21: // Ctrl-U
+			buf = buf[pos:]
+			pos = 0
+		case '\t':
+			buf, pos = r.complete(buf, pos)
+		case 27:
+			if next, _, _ := r.in.ReadRune(); next != '[' {
+				continue
+			}
+			switch key, _, _ := r.in.ReadRune(); key {
+			case 'A':
+				if histPos > 0 {
+					fromHistory(histPos - 1)
+				}
+			case 'B':
+				if histPos < len(r.history) {
+					fromHistory(histPos + 1)
+				}
+			case 'C':
+				if pos < len(buf) {
+					pos++
+				}
+			case 'D':
+				if pos > 0 {
+					pos--
+				}
+			case 'H':
+				pos = 0
+			case 'F':
+				pos = len(buf)
+			case '3':
+				if tilde, _, _ := r.in.ReadRune(); tilde == '~' && pos < len(buf) {
+					buf = append(buf[:pos], buf[pos+1:]...)
+				}
+			}
+		default:
+			if ch >= ' ' {
+				buf = append(buf[:pos], append([]rune{ch}, buf[pos:]...)...)
+				pos++
+			}
+		}
+		redraw()
+	}
+}
+
+// complete completes the word before the cursor. A single candidate replaces
+// the word, several candidates are extended to their common prefix or listed.
+func (r *shellReader) complete(buf []rune, pos int) ([]rune, int) {
+	before := string(buf[:pos])
+	words, start, _ := splitShellWords(before)
+	toComplete := ""
+	if start < len(before) {
+		toComplete = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+
+	candidates, directive := r.cmd.shellCompletions(words, toComplete)
+	if len(candidates) == 0 {
+		return buf, pos
+	}
+
+	prefix := commonPrefix(candidates)
+	if len(candidates) > 1 && prefix == toComplete {
+		out := r.cmd.OutOrStdout()
+		fmt.Fprint(out, "\r\n"+strings.Join(candidates, "  ")+"\r\n")
+		return buf, pos
+	}
+
+	replacement := shellEscape(prefix)
+	if len(candidates) == 1 && directive&ShellCompDirectiveNoSpace == 0 && !strings.HasSuffix(prefix, "/") {
+		replacement += " "
+	}
+	head := append([]rune(before[:start]), []rune(replacement)...)
+	return append(head, buf[pos:]...), len(head)
+}
+
+// shellCompletions returns the candidates to complete toComplete after args,
+// as the shell completion scripts would.
+func (c *Command) shellCompletions(args []string, toComplete string) ([]string, ShellCompDirective) {
+	root := c.Root()
+	_, completions, directive, err := root.getCompletions(append(args, toComplete))
+	if resetErr := root.resetFlagValues(); err == nil {
+		err = resetErr
+	}
+	if err != nil || directive&ShellCompDirectiveError != 0 {
+		return nil, ShellCompDirectiveError
+	}
+
+	switch {
+	case directive&ShellCompDirectiveFilterFileExt != 0:
+		return fileCompletions(toComplete, "", completions, false), directive
+	case directive&ShellCompDirectiveFilterDirs != 0:
+		dir := ""
+		if len(completions) == 1 {
+			dir = completions[0]
+		}
+		return fileCompletions(toComplete, dir, nil, true), directive
+	}
+
+	var candidates []string
+	for _, choice := range completionChoices(completions, directive) {
+		if strings.HasPrefix(choice, toComplete) {
+			candidates = append(candidates, choice)
+		}
+	}
+	if len(candidates) == 0 && directive&ShellCompDirectiveNoFileComp == 0 {
+		candidates = fileCompletions(toComplete, "", nil, false)
+	}
+	return candidates, directive
+}
+
+// fileCompletions returns the files and directories starting with
+// toComplete, relative to dir. Files are only kept if they have one of exts,
+// or if exts is empty and dirsOnly is not set. Directories end with a slash.
+func fileCompletions(toComplete, dir string, exts []string, dirsOnly bool) []string {
+	matches, _ := filepath.Glob(filepath.Join(dir, toComplete) + "*")
+
+	var candidates []string
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil {
+			continue
+		}
+		if dir != "" {
+			if match, err = filepath.Rel(dir, match); err != nil {
+				continue
+			}
+		}
+		if toComplete != "" && !strings.HasPrefix(match, toComplete) {
+			// filepath.Join cleaned a prefix such as ./
+			match = toComplete + strings.TrimPrefix(match, filepath.Clean(toComplete))
+		}
+
+		if info.IsDir() {
+			candidates = append(candidates, match+"/")
+			continue
+		}
+		if dirsOnly {
+			continue
+		}
+		if len(exts) > 0 {
+			ext := strings.TrimPrefix(filepath.Ext(match), ".")
+			found := false
+			for _, e := range exts {
+				found = found || e == ext
+			}
+			if !found {
+				continue
+			}
+		}
+		candidates = append(candidates, match)
+	}
+	return candidates
+}
+
+func commonPrefix(values []string) string {
+	prefix := []rune(values[0])
+	for _, v := range values[1:] {
+		runes := []rune(v)
+		n := 0
+		for n < len(prefix) && n < len(runes) && prefix[n] == runes[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
@@ -2,12 +2,12 @@ package cobra
 
 import "github.com/spf13/pflag"
 
-func (c *Command) MarkFlagRequire(name string) error {
-	return MarkFlagRequire(c.Flags(), name)
+func (c *Command) MarkFlagRequired(name string) error {
+	return MarkFlagRequired(c.Flags(), name)
 }
 
 func (c *Command) MarkPersistentFlagRequired(name string) error {
-	return MarkFlagRequired(c.persistentFlag(), name)
+	return MarkFlagRequired(c.PersistentFlags(), name)
 }
 
 func MarkFlagRequired(flags *pflag.FlagSet, name string) error {
@@ -15,7 +15,7 @@ func MarkFlagRequired(flags *pflag.FlagSet, name string) error {
 }
 
 func (c *Command) MarkFlagFilename(name string, extensions ...string) error {
-	return c.MarkFlagFilename(c.Flags(), name, extensions...)
+	return MarkFlagFilename(c.Flags(), name, extensions...)
 }
 
 func (c *Command) MarkFlagCustom(name string, f string) error {
@@ -23,7 +23,7 @@ func (c *Command) MarkFlagCustom(name string, f string) error {
 }
 
 func (c *Command) MarkPersistentFlagFilename(name string, extensions ...string) error {
-	return c.MarkFlagFilename(c.PersistentFlags(), name, extensions...)
+	return MarkFlagFilename(c.PersistentFlags(), name, extensions...)
 }
 
 func MarkFlagFilename(flags *pflag.FlagSet, name string, extensions ...string) error {
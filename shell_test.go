@@ -0,0 +1,44 @@
+package cobra
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestShellCompletionDoesNotChangeTree(t *testing.T) {
+	rootCmd := &Command{Use: "root"}
+	var ran bool
+	subCmd := &Command{Use: "sub", Run: func(*Command, []string) { ran = true }}
+	subCmd.Flags().Bool("a", false, "")
+	subCmd.Flags().Bool("b", false, "")
+	subCmd.Flags().Bool("json", false, "")
+	subCmd.Flags().Bool("yaml", false, "")
+	subCmd.MarkFlagsOneRequired("a", "b")
+	subCmd.MarkFlagsMutuallyExclusive("json", "yaml")
+	rootCmd.AddCommand(subCmd)
+
+	// a TAB press after "sub --json" makes --a and --b required and hides --yaml
+	if completions, _ := subCmd.shellCompletions([]string{"sub", "--json"}, "-"); len(completions) == 0 {
+		t.Fatal("Expected completions")
+	}
+	for _, name := range []string{"a", "b"} {
+		if f := subCmd.Flags().Lookup(name); f.Annotations[BashCompOneRequiredFlag] != nil {
+			t.Errorf("Expected --%s not to stay required", name)
+		}
+	}
+	if subCmd.Flags().Lookup("yaml").Hidden {
+		t.Error("Expected --yaml not to stay hidden")
+	}
+
+	out := new(bytes.Buffer)
+	rootCmd.SetOutput(out)
+	rootCmd.SetIn(strings.NewReader("sub --a --yaml\n"))
+	if err := rootCmd.RunShell(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !ran {
+		t.Errorf("Expected sub to run, got:\n%s", out.String())
+	}
+}
@@ -0,0 +1,128 @@
+package cobra
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// signalHandler cancels the context of an execution on the first SIGINT or
+// SIGTERM, and exits the program on the second one or when the grace period
+// after the first one is over.
+type signalHandler struct {
+	signals chan os.Signal
+	cancel  context.CancelFunc
+	grace   time.Duration
+	// received is closed on the first signal, after deadline is set.
+	received chan struct{}
+	deadline time.Time
+	done     chan struct{}
+
+	graceCtx    context.Context
+	graceCancel context.CancelFunc
+}
+
+func notifySignals(parent context.Context, grace time.Duration) (context.Context, *signalHandler) {
+	ctx, cancel := context.WithCancel(parent)
+	h := &signalHandler{
+		signals:  make(chan os.Signal, 2),
+		cancel:   cancel,
+		grace:    grace,
+		received: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	signal.Notify(h.signals, os.Interrupt, syscall.SIGTERM)
+	go h.run()
+	return ctx, h
+}
+
+func (h *signalHandler) run() {
+	select {
+	case <-h.signals:
+	case <-h.done:
+		return
+	}
+	h.deadline = time.Now().Add(h.grace)
+	close(h.received)
+	h.cancel()
+
+	var timeout <-chan time.Time
+	if h.grace > 0 {
+		timer := time.NewTimer(h.grace)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-h.signals:
+	case <-timeout:
+	case <-h.done:
+		return
+	}
+	os.Exit(ExitCanceled)
+}
+
+func (h *signalHandler) interrupted() bool {
+	select {
+	case <-h.received:
+		return true
+	default:
+		return false
+	}
+}
+
+// gracePeriod returns the context of the cleanup after the first signal,
+// which is not cancelled by it but ends with the grace period.
+func (h *signalHandler) gracePeriod(ctx context.Context) context.Context {
+	if h.graceCtx == nil {
+		h.graceCtx = context.WithoutCancel(ctx)
+		if h.grace > 0 {
+			h.graceCtx, h.graceCancel = context.WithDeadline(h.graceCtx, h.deadline)
+		}
+	}
+	return h.graceCtx
+}
+
+func (h *signalHandler) stop() {
+	signal.Stop(h.signals)
+	close(h.done)
+	h.cancel()
+	if h.graceCancel != nil {
+		h.graceCancel()
+	}
+}
+
+// interrupted reports whether the context of the execution of c was
+// cancelled by a signal.
+func (c *Command) interrupted() bool {
+	h := c.Root().signals
+	return h != nil && h.interrupted()
+}
+
+// setPathContext sets the context of c and its parents to ctx, and returns
+// a function setting their previous contexts back.
+func (c *Command) setPathContext(ctx context.Context) func() {
+	saved := map[*Command]context.Context{}
+	for p := c; p != nil; p = p.Parent() {
+		saved[p] = p.ctx
+		p.ctx = ctx
+	}
+	return func() {
+		for p, ctx := range saved {
+			p.ctx = ctx
+		}
+	}
+}
+
+// enterGracePeriod replaces the context of c and its parents, once it was
+// cancelled by a signal, by the one of the grace period, so that the post-run
+// hooks and the finalizers can still use it to clean up.
+func (c *Command) enterGracePeriod() {
+	if c.interrupted() {
+		ctx := c.Root().signals.gracePeriod(c.ctx)
+		for p := c; p != nil; p = p.Parent() {
+			p.ctx = ctx
+		}
+	}
+}
@@ -0,0 +1,143 @@
+//go:build !windows
+
+package cobra
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// interruptSelf sends SIGINT to the test process, and waits for ctx to be
+// cancelled by it.
+func interruptSelf(t *testing.T, ctx context.Context) error {
+	t.Helper()
+	if err := syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the context to be cancelled by the signal")
+		return nil
+	}
+}
+
+func TestCancelOnSignal(t *testing.T) {
+	ctx := context.Background()
+	rootCmd := &Command{Use: "root", CancelOnSignal: true}
+	var parentErr error
+	childCmd := &Command{
+		Use: "child",
+		RunE: func(cmd *Command, args []string) error {
+			err := interruptSelf(t, cmd.Context())
+			// the parents see the cancelled context too
+			parentErr = rootCmd.Context().Err()
+			return err
+		},
+	}
+	rootCmd.AddCommand(childCmd)
+	rootCmd.SetOutput(new(bytes.Buffer))
+	rootCmd.SetArgs([]string{"child"})
+
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if code := cmd.ExitCode(err); code != ExitCanceled {
+		t.Errorf("Expected exit code %d, got %d", ExitCanceled, code)
+	}
+	if !errors.Is(parentErr, context.Canceled) {
+		t.Errorf("Expected the context of the root command to be cancelled, got %v", parentErr)
+	}
+	if rootCmd.Context() != ctx {
+		t.Error("Expected the context of the root command to be set back")
+	}
+	if rootCmd.signals != nil {
+		t.Error("Expected the signals not to be handled after the execution")
+	}
+}
+
+func TestCancelOnSignalGracePeriod(t *testing.T) {
+	var cleanupErr error
+	var deadline time.Time
+	rootCmd := &Command{
+		Use:               "root",
+		CancelOnSignal:    true,
+		SignalGracePeriod: time.Minute,
+		RunE: func(cmd *Command, args []string) error {
+			return interruptSelf(t, cmd.Context())
+		},
+		PersistentPostRun: func(cmd *Command, args []string) {
+			cleanupErr = cmd.Context().Err()
+			deadline, _ = cmd.Context().Deadline()
+		},
+	}
+	rootCmd.SetOutput(new(bytes.Buffer))
+	rootCmd.SetArgs(nil)
+
+	if err := rootCmd.Execute(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if cleanupErr != nil {
+		t.Errorf("Expected the post-run hook to get a live context, got %v", cleanupErr)
+	}
+	if deadline.IsZero() || time.Until(deadline) > time.Minute {
+		t.Errorf("Expected the grace period as the deadline, got %v", deadline)
+	}
+}
+
+// signalHelperEnv names the case TestCancelOnSignalExits runs in a child process.
+const signalHelperEnv = "COBRA_TEST_SIGNAL_CASE"
+
+func TestCancelOnSignalExits(t *testing.T) {
+	if name := os.Getenv(signalHelperEnv); name != "" {
+		runSignalCase(t, name)
+		return
+	}
+
+	for _, name := range []string{"second signal", "grace period"} {
+		t.Run(name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0], "-test.run=^TestCancelOnSignalExits$")
+			cmd.Env = append(os.Environ(), signalHelperEnv+"="+name)
+			err := cmd.Run()
+
+			var exitErr *exec.ExitError
+			if !errors.As(err, &exitErr) {
+				t.Fatalf("Expected the process to exit with an error, got %v", err)
+			}
+			if code := exitErr.ExitCode(); code != ExitCanceled {
+				t.Errorf("Expected exit code %d, got %d", ExitCanceled, code)
+			}
+		})
+	}
+}
+
+// runSignalCase runs a command which does not return once its context is
+// cancelled, so that only the exit on the second signal or at the end of the
+// grace period ends the process.
+func runSignalCase(t *testing.T, name string) {
+	rootCmd := &Command{
+		Use:            "root",
+		CancelOnSignal: true,
+		Run: func(cmd *Command, args []string) {
+			_ = interruptSelf(t, cmd.Context())
+			if name == "second signal" {
+				_ = syscall.Kill(os.Getpid(), syscall.SIGINT)
+			}
+			time.Sleep(10 * time.Second)
+		},
+	}
+	if name == "grace period" {
+		rootCmd.SignalGracePeriod = 10 * time.Millisecond
+	}
+	rootCmd.SetArgs(nil)
+	_ = rootCmd.Execute()
+	os.Exit(0)
+}
@@ -0,0 +1,28 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package cobra
+
+import (
+	"errors"
+	"os"
+)
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// setEcho fails, as the echo of the terminal input cannot be turned off here.
+// Sensitive values are then not prompted for.
+func setEcho(fd uintptr, on bool) error {
+	if on {
+		return nil
+	}
+	return errors.New("masking the input is not supported on this platform")
+}
+
+// makeRaw fails, as the terminal cannot be put in raw mode here.
+// Lines are then read without editing.
+func makeRaw(fd uintptr) (func(), error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
@@ -0,0 +1,67 @@
+package cobra
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenZshCompletion(t *testing.T) {
+	rootCmd := &Command{Use: "prog", Run: emptyRun}
+	rootCmd.AddCommand(&Command{Use: "child", Run: emptyRun})
+
+	tests := []struct {
+		gen     func(*Command, *bytes.Buffer) error
+		compCmd string
+	}{
+		{func(c *Command, buf *bytes.Buffer) error { return c.GenZshCompletion(buf) }, ShellCompRequestCmd},
+		{func(c *Command, buf *bytes.Buffer) error { return c.GenZshCompletionNoDesc(buf) }, ShellCompNoDescRequestCmd},
+	}
+	for _, tc := range tests {
+		buf := new(bytes.Buffer)
+		if err := tc.gen(rootCmd, buf); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		out := buf.String()
+
+		expected := []string{
+			"#compdef prog\n",
+			"compdef _prog prog\n",
+			"_prog()\n",
+			fmt.Sprintf(`requestComp="${words[1]} %s ${words[2,-1]}"`, tc.compCmd),
+			fmt.Sprintf("local shellCompDirectiveError=%d\n", ShellCompDirectiveError),
+			fmt.Sprintf("local shellCompDirectiveNoSpace=%d\n", ShellCompDirectiveNoSpace),
+			fmt.Sprintf("local shellCompDirectiveNoFileComp=%d\n", ShellCompDirectiveNoFileComp),
+			fmt.Sprintf("local shellCompDirectiveFilterFileExt=%d\n", ShellCompDirectiveFilterFileExt),
+			fmt.Sprintf("local shellCompDirectiveFilterDirs=%d\n", ShellCompDirectiveFilterDirs),
+			fmt.Sprintf("local shellCompDirectiveKeepOrder=%d\n", ShellCompDirectiveKeepOrder),
+		}
+		for _, s := range expected {
+			if !strings.Contains(out, s) {
+				t.Errorf("Expected %q in the %s script", s, tc.compCmd)
+			}
+		}
+		if strings.Contains(out, "%!") {
+			t.Errorf("Unexpected formatting error in the %s script", tc.compCmd)
+		}
+	}
+}
+
+func TestGenZshCompletionFile(t *testing.T) {
+	rootCmd := &Command{Use: "prog", Run: emptyRun}
+	filename := filepath.Join(t.TempDir(), "_prog")
+
+	if err := rootCmd.GenZshCompletionFile(filename); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "#compdef prog\n") {
+		t.Errorf("Expected a zsh script for prog, got:\n%s", data)
+	}
+}